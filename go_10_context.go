@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Context basics
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// A context.Context gets passed down through function calls (always the first param, named ctx)
+	// and carries two things:
+	//   - a "stop" signal: cancelled, or a deadline passed, so stop working
+	//   - request scoped values: request ID, logged in user...
+	//
+	// Python's closest is asyncio task cancellation, JS's is AbortController.
+	ctx := context.Background() // the empty root context, start here in main
+
+	// WithTimeout makes a child context that cancels itself after 50ms.
+	// ALWAYS defer cancel(), it frees the timer even if we finish early.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-time.After(time.Second): // pretend this is a slow db call
+		fmt.Println("finished the slow work")
+	case <-timeoutCtx.Done(): // Done is a channel, closed when the context is cancelled
+		fmt.Println(timeoutCtx.Err()) // context deadline exceeded
+	}
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Context values, and how to key them
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	ctx = withRequestID(ctx, "req-42")
+	fmt.Println(requestID(ctx)) // req-42
+
+	// Child contexts see their parents' values.
+	childCtx, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	fmt.Println(requestID(childCtx)) // req-42
+
+	// Missing values come back as "", no panic.
+	fmt.Println(requestID(context.Background()) == "") // true
+
+	// Why not just context.WithValue(ctx, "requestID", id)?
+	//
+	// Keys compare by type AND value. Any package that also uses the string
+	// "requestID" (your logging lib, some middleware) would read or overwrite ours.
+	// Linters like staticcheck flag built-in key types for exactly this reason.
+	ctx = context.WithValue(ctx, "requestID", "someone else's ID")
+	fmt.Println(requestID(ctx)) // req-42, still ours, the string key can't touch a ctxKey key
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// A timeout per item
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// One stuck item shouldn't hold up the whole batch. Give each its own deadline.
+	items := []workItem{
+		{Name: "fast 1", Takes: 5 * time.Millisecond},
+		{Name: "slow 1", Takes: time.Second},
+		{Name: "fast 2", Takes: 5 * time.Millisecond},
+		{Name: "slow 2", Takes: time.Second},
+	}
+	timedOut := processWithTimeouts(context.Background(), items, 50*time.Millisecond)
+	fmt.Println(timedOut) // [slow 1 slow 2], the fast ones finished, and it took ~100ms not 2s
+}
+
+// workItem is some pretend work that Takes a while.
+type workItem struct {
+	Name  string
+	Takes time.Duration
+}
+
+// process does the pretend work, giving up if ctx is cancelled first.
+// Real code passes ctx down to whatever is slow (db.QueryContext, http.NewRequestWithContext...).
+func process(ctx context.Context, item workItem) error {
+	select {
+	case <-time.After(item.Takes):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// processWithTimeouts runs every item with its own perItem deadline,
+// skips (and logs) any that run out of time, and keeps going with the rest.
+// It returns the names of the ones that timed out.
+//
+// Each item's context is a child of ctx, so cancelling ctx still stops everything.
+func processWithTimeouts(ctx context.Context, items []workItem, perItem time.Duration) []string {
+	var timedOut []string
+	for _, item := range items {
+		itemCtx, cancel := context.WithTimeout(ctx, perItem)
+		err := process(itemCtx, item)
+		cancel() // NOT defer, that would wait until the whole loop ends, keeping every timer alive
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Printf("skipping %s, it took longer than %v", item.Name, perItem)
+			timedOut = append(timedOut, item.Name)
+			continue
+		}
+		if err != nil {
+			log.Printf("%s failed: %v", item.Name, err) // cancelled, or the work itself failed
+			continue
+		}
+		fmt.Println("processed " + item.Name)
+	}
+	return timedOut
+}
+
+// ctxKey is a private type just for our context keys.
+//
+// Nobody outside this package can make a ctxKey, so nobody can collide with us,
+// even if they use the same underlying number.
+type ctxKey int
+
+// One const per value we store, iota counts up 0, 1, 2...
+const (
+	requestIDKey ctxKey = iota
+	// userKey  <-- the next one would go here
+)
+
+// withRequestID returns a copy of ctx carrying id.
+// Contexts never change, every With... makes a new child.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// requestID reads the ID back out, "" if it was never set.
+//
+// Wrapping the key in getter/setter funcs means nobody else ever
+// touches requestIDKey or the type assertion.
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string) // Value returns any, the ", _" form can't panic
+	return id
+}
@@ -0,0 +1,87 @@
+package main
+
+//	go test go_10_context.go go_10_context_test.go
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestIDRoundTrip(t *testing.T) {
+	ctx := withRequestID(context.Background(), "abc-123")
+	if got := requestID(ctx); got != "abc-123" {
+		t.Errorf("requestID = %q, want abc-123", got)
+	}
+
+	// Children see their parent's values, and a new With... only shadows it for its own subtree.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if got := requestID(child); got != "abc-123" {
+		t.Errorf("child: requestID = %q, want abc-123", got)
+	}
+	if got := requestID(withRequestID(child, "def-456")); got != "def-456" {
+		t.Errorf("overridden: requestID = %q, want def-456", got)
+	}
+	if got := requestID(ctx); got != "abc-123" {
+		t.Errorf("parent after override: requestID = %q, want abc-123 unchanged", got)
+	}
+
+	if got := requestID(context.Background()); got != "" {
+		t.Errorf("never set: requestID = %q, want \"\"", got)
+	}
+	// Same underlying number, different type, so it's a different key. That's the whole point.
+	if got := requestID(context.WithValue(context.Background(), 0, "nope")); got != "" {
+		t.Errorf("int key 0: requestID = %q, want \"\"", got)
+	}
+}
+
+func TestProcessWithTimeoutsSkipsOnlyTheSlowOnes(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	items := []workItem{
+		{Name: "fast 1", Takes: time.Millisecond},
+		{Name: "slow 1", Takes: time.Hour},
+		{Name: "fast 2", Takes: 0},
+		{Name: "slow 2", Takes: time.Hour},
+		{Name: "fast 3", Takes: time.Millisecond},
+	}
+	start := time.Now()
+	timedOut := processWithTimeouts(context.Background(), items, 50*time.Millisecond)
+
+	if want := []string{"slow 1", "slow 2"}; !slices.Equal(timedOut, want) {
+		t.Errorf("timed out = %q, want %q", timedOut, want)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("took %v, the slow items should have been cut off at 50ms each", took)
+	}
+	for _, name := range []string{"slow 1", "slow 2"} {
+		if !strings.Contains(logs.String(), "skipping "+name) {
+			t.Errorf("logs = %q, want %q skipped", logs.String(), name)
+		}
+	}
+}
+
+func TestProcessWithTimeoutsParentCancelled(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Cancelled isn't the same as timed out, so nothing is reported, every item just fails.
+	timedOut := processWithTimeouts(ctx, []workItem{{Name: "a", Takes: time.Hour}, {Name: "b", Takes: time.Hour}}, time.Hour)
+	if len(timedOut) != 0 {
+		t.Errorf("timed out = %q, want none", timedOut)
+	}
+	if strings.Count(logs.String(), "context canceled") != 2 {
+		t.Errorf("logs = %q, want both items failed with context canceled", logs.String())
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// go_2_funcs.go warns that closures capturing variables can "leak" memory.
+// Here's how to actually measure it instead of taking my word for it.
+//
+//	go run go_11_closure_bench.go
+//
+// Benchmarks normally live in a _test.go file and run with
+//
+//	go test -bench . -benchmem
+//
+// testing.Benchmark runs the exact same benchmark functions from a normal
+// program, so this file works without a go.mod or test files.
+// Both print the same columns, like:
+//
+//	 261457     4019 ns/op    32800 B/op    2 allocs/op
+//	   |          |             |             |
+//	   |          |             |             heap allocations per loop (each one is work for the garbage collector)
+//	   |          |             bytes allocated on the heap per loop
+//	   |          time per loop
+//	   how many loops (b.N) it took to get a stable measurement
+//
+// To see WHERE the memory goes, profile it:
+//
+//	go test -bench . -memprofile mem.out
+//	go tool pprof -alloc_space mem.out
+//	(pprof) top           <-- biggest allocators, the capturing closure's func is at the top
+//	(pprof) list Capture  <-- line by line, points right at the make([]byte...)
+//
+// In a long running server, import _ "net/http/pprof" and hit /debug/pprof/heap instead.
+
+// bufferSize is 32KB, a chunk of data a closure might hang onto by accident.
+const bufferSize = 32 << 10
+
+// sink stops the compiler from noticing our closures are never used and
+// deleting them (which would make the benchmark measure nothing).
+var sink func() int
+
+// BenchmarkCapturingClosure makes a closure that captures the whole buffer.
+//
+// Because the closure outlives the loop iteration (it's stored in sink), the
+// compiler has to move buf to the heap so it stays alive, all 32KB of it.
+// Every closure kept around keeps its whole buffer around.
+func BenchmarkCapturingClosure(b *testing.B) {
+	b.ReportAllocs() // same as -benchmem, show B/op and allocs/op
+	for i := 0; i < b.N; i++ {
+		buf := make([]byte, bufferSize)
+		sink = func() int {
+			return len(buf) // ❌ captures buf, the closure holds the 32KB
+		}
+	}
+}
+
+// BenchmarkNonCapturingClosure makes the same closure but only captures
+// what it needs, the length. buf never escapes, so it's (cheaply) on the stack,
+// and the closure is just a few bytes.
+func BenchmarkNonCapturingClosure(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := make([]byte, bufferSize)
+		n := len(buf)
+		sink = func() int {
+			return n // ✅ captures an int, buf is free to go
+		}
+	}
+}
+
+func main() {
+	capturing := testing.Benchmark(BenchmarkCapturingClosure)
+	nonCapturing := testing.Benchmark(BenchmarkNonCapturingClosure)
+
+	fmt.Println("capturing:    ", capturing, capturing.MemString())       // ~32800 B/op 2 allocs/op
+	fmt.Println("non-capturing:", nonCapturing, nonCapturing.MemString()) // ~16 B/op 1 allocs/op
+
+	// The "leak" part: keep 1000 closures around (like callbacks in a registry)
+	// and check how much heap is still in use after a garbage collection.
+	fmt.Printf("1000 capturing closures keep    %6d KB alive\n", retainedKB(true))   // ~32000 KB
+	fmt.Printf("1000 non-capturing closures keep %6d KB alive\n", retainedKB(false)) // ~16 KB, just the tiny closures
+}
+
+// retainedKB keeps 1000 closures alive and reports the heap they hold onto.
+func retainedKB(capture bool) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	kept := make([]func() int, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		buf := make([]byte, bufferSize)
+		if capture {
+			kept = append(kept, func() int { return len(buf) })
+		} else {
+			n := len(buf)
+			kept = append(kept, func() int { return n })
+		}
+	}
+
+	runtime.GC() // collect everything unreachable, what's left is really held
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(kept) // kept has to survive until after we measure
+
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return (after.HeapAlloc - before.HeapAlloc) / 1024
+}
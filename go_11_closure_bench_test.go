@@ -0,0 +1,40 @@
+package main
+
+//	go test go_11_closure_bench.go go_11_closure_bench_test.go
+//	go test -bench . -benchmem go_11_closure_bench.go go_11_closure_bench_test.go
+//
+// go test only looks for Benchmark funcs in _test.go files, so BenchmarkClosures
+// hands the two from go_11_closure_bench.go to b.Run. The output looks like:
+//
+//	BenchmarkClosures/capturing-8        261457    4019 ns/op   32800 B/op   2 allocs/op
+//	BenchmarkClosures/non-capturing-8  50000000      24 ns/op      16 B/op   1 allocs/op
+
+import "testing"
+
+func BenchmarkClosures(b *testing.B) {
+	b.Run("capturing", BenchmarkCapturingClosure)
+	b.Run("non-capturing", BenchmarkNonCapturingClosure)
+}
+
+func TestCapturingClosureAllocatesTheBuffer(t *testing.T) {
+	capturing := testing.Benchmark(BenchmarkCapturingClosure)
+	nonCapturing := testing.Benchmark(BenchmarkNonCapturingClosure)
+
+	if got := capturing.AllocedBytesPerOp(); got < bufferSize {
+		t.Errorf("capturing: %d B/op, want at least the %d byte buffer", got, bufferSize)
+	}
+	if got := nonCapturing.AllocedBytesPerOp(); got >= bufferSize {
+		t.Errorf("non-capturing: %d B/op, want much less than the %d byte buffer", got, bufferSize)
+	}
+}
+
+func TestRetainedKB(t *testing.T) {
+	capturing, nonCapturing := retainedKB(true), retainedKB(false)
+	// 1000 closures times 32KB is ~32000KB, leave lots of room for the GC's own bookkeeping.
+	if capturing < 30000 {
+		t.Errorf("capturing closures keep %d KB alive, want ~32000", capturing)
+	}
+	if nonCapturing > 1000 {
+		t.Errorf("non-capturing closures keep %d KB alive, want just a few", nonCapturing)
+	}
+}
@@ -0,0 +1,587 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// go_3_goroutines.go covers goroutines, channels and select.
+// This file builds small, reusable helpers out of them.
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Blocking queue
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// 3 producers and 3 consumers sharing one small queue.
+	queue := NewBlockingQueue[int](2)
+
+	var producers sync.WaitGroup
+	for p := 0; p < 3; p++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			for i := 0; i < 100; i++ {
+				_ = queue.Put(i) // blocks while the queue is full, that's the point
+			}
+		}()
+	}
+
+	var consumers sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for c := 0; c < 3; c++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for {
+				_, ok := queue.Take() // blocks while empty
+				if !ok {
+					return // closed and drained
+				}
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producers.Wait() // all 300 are in (or already taken)
+	queue.Close()    // wakes up the consumers waiting on an empty queue
+	consumers.Wait()
+	fmt.Println(taken) // 300, nothing lost
+
+	fmt.Println(queue.Put(1)) // queue closed
+	_, ok := queue.Take()
+	fmt.Println(ok) // false
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Debounce
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Someone hammering "save", 10 times in quick succession.
+	var saves atomic.Int32 // the debounced func runs on another goroutine, so count safely
+	save := Debounce(50*time.Millisecond, func() {
+		saves.Add(1)
+	})
+	for i := 0; i < 10; i++ {
+		save()
+		time.Sleep(5 * time.Millisecond) // much quicker than 50ms, keeps pushing the timer back
+	}
+	fmt.Println(saves.Load()) // 0, still waiting for things to quiet down
+	time.Sleep(100 * time.Millisecond)
+	fmt.Println(saves.Load()) // 1, ran once, 50ms after the LAST call
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Throttle
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// A scroll handler firing every 10ms for 250ms, 25 calls.
+	// Throttled to once per 100ms, it gets through at ~0ms, ~100ms and ~200ms.
+	scrolls := 0
+	onScroll := Throttle(100*time.Millisecond, func() {
+		scrolls++ // fn runs on OUR goroutine, no atomic needed here
+	})
+	for i := 0; i < 25; i++ {
+		onScroll()
+		time.Sleep(10 * time.Millisecond)
+	}
+	fmt.Println(scrolls) // 3, the other 22 calls were dropped
+
+	// Debounce vs Throttle, with someone typing constantly for 1 second and a 100ms delay:
+	//   Debounce: fn runs ONCE, 100ms after they stop.         "wait until it's quiet"
+	//   Throttle: fn runs ~10 times, right away then every 100ms. "no more often than"
+	// Debounce for search-as-you-type and autosave, Throttle for scroll/resize handlers and rate limits.
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Tee
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// One stream of prices, two consumers: one saves them, one checks for alerts.
+	prices := make(chan int)
+	go func() {
+		defer close(prices)
+		for _, price := range []int{100, 101, 99, 105} {
+			prices <- price
+		}
+	}()
+
+	toSave, toCheck := Tee(prices)
+
+	// Both outputs MUST be read at the same time, see Tee's comment.
+	var saved, checked []int
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		for price := range toSave {
+			saved = append(saved, price)
+		}
+	}()
+	go func() {
+		defer readers.Done()
+		for price := range toCheck {
+			time.Sleep(time.Millisecond) // a slower consumer, toSave just waits for it
+			checked = append(checked, price)
+		}
+	}()
+	readers.Wait()
+	fmt.Println(saved, checked)               // [100 101 99 105] [100 101 99 105]
+	fmt.Println(slices.Equal(saved, checked)) // true
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Round robin
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// In go_3_goroutines.go all 4 stock workers read the SAME channel, and whoever
+	// happens to be free grabs the next ticker, you can't say who gets what.
+	// Here each worker has its own channel and we deal items out like cards.
+	tickers := make(chan string)
+	go func() {
+		defer close(tickers)
+		for _, ticker := range []string{"AAPL", "GOOG", "FB", "AMZN", "MSFT", "NFLX"} {
+			tickers <- ticker
+		}
+	}()
+
+	inboxes := make([]chan string, 3)
+	sendOnly := make([]chan<- string, 3) // a []chan string isn't a []chan<- string, convert one by one
+	for i := range inboxes {
+		inboxes[i] = make(chan string)
+		sendOnly[i] = inboxes[i]
+	}
+	go roundRobin(tickers, sendOnly)
+
+	received := make([][]string, 3)
+	var workersWg sync.WaitGroup
+	for i, inbox := range inboxes {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			for ticker := range inbox {
+				received[i] = append(received[i], ticker) // each goroutine only touches its own index, no lock needed
+			}
+		}()
+	}
+	workersWg.Wait()
+	fmt.Println(received) // [[AAPL AMZN] [GOOG MSFT] [FB NFLX]], 2 each, in order
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Resizable worker pool
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	pool := NewPool(2)
+	fmt.Println(pool.Workers()) // 2
+
+	var done atomic.Int32
+	var busy, mostBusy atomic.Int32 // how many jobs are running right now, and the most at once
+	job := func() {
+		now := busy.Add(1)
+		for { // mostBusy = max(mostBusy, now), CompareAndSwap only stores if nobody changed it in between
+			most := mostBusy.Load()
+			if now <= most || mostBusy.CompareAndSwap(most, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		busy.Add(-1)
+		done.Add(1)
+	}
+
+	// Keep it loaded while we resize.
+	var submitters sync.WaitGroup
+	submitters.Add(1)
+	go func() {
+		defer submitters.Done()
+		for i := 0; i < 60; i++ {
+			_ = pool.Submit(job)
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	pool.Resize(5)              // traffic spike, more workers
+	fmt.Println(pool.Workers()) // 5
+	time.Sleep(20 * time.Millisecond)
+	pool.Resize(1)              // quiet again, waits for the 4 stopped workers' current jobs
+	fmt.Println(pool.Workers()) // 1
+
+	submitters.Wait()
+	pool.Resize(-1)             // same as 0, not a panic
+	fmt.Println(pool.Workers()) // 0
+	pool.Resize(2)
+	pool.Close()
+	fmt.Println(pool.Workers(), done.Load()) // 0 60, every job ran
+	fmt.Println(mostBusy.Load())             // 5, never more jobs at once than workers
+	fmt.Println(pool.Submit(job))            // pool closed
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Run all, collect every error
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Check 4 services at once, the slowest one decides how long it takes.
+	start := time.Now()
+	errs := RunAll(
+		func() error { time.Sleep(30 * time.Millisecond); return nil },
+		func() error { time.Sleep(10 * time.Millisecond); return errors.New("cache is down") },
+		func() error { time.Sleep(20 * time.Millisecond); return nil },
+		func() error { return errors.New("queue is down") }, // fails first, but still ends up last
+	)
+	fmt.Println(errs)                                    // [<nil> cache is down <nil> queue is down]
+	fmt.Println(time.Since(start) < 60*time.Millisecond) // true, ~30ms, not 10+20+30
+	fmt.Println(errors.Join(errs...))                    // cache is down\nqueue is down, one error for all of them
+}
+
+// RunAll runs every fn at once, waits for all of them, and returns their
+// errors in the same order as fns, errs[i] is fns[i]'s (nil if it worked).
+//
+// Like python's asyncio.gather(..., return_exceptions=True) or JS's Promise.allSettled.
+//
+// golang.org/x/sync/errgroup is the other common way, it's Promise.all:
+// Wait returns just the FIRST error, and its context is cancelled as soon as
+// one fails, so the others can stop early. Use errgroup when one failure
+// means the whole thing failed, RunAll when you want to know about each one
+// (health checks, sending to many users, a batch where some may fail).
+func RunAll(fns ...func() error) []error {
+	errs := make([]error, len(fns))
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each goroutine writes only its own slot, so no mutex needed.
+			// It's appending to a shared slice that would need one.
+			errs[i] = fn()
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// ErrQueueClosed is returned by Put after Close.
+var ErrQueueClosed = errors.New("queue closed")
+
+// BlockingQueue is a goroutine safe FIFO queue with a max size,
+// like python's queue.Queue(maxsize=n).
+//
+// Isn't that just a buffered channel? Almost, it IS one inside. What it adds:
+//   - Close can be called any time, even with Puts in flight, and never panics.
+//     Sending on a closed raw channel panics, and so does closing it twice.
+//   - Put after Close returns an error instead of crashing.
+//   - Take after Close still hands out what's left, then reports ok == false.
+//
+// Unlike the "ALWAYS INCLUDE default FOR CHANNEL WRITES" rule in go_3, Put
+// blocks on purpose. Slowing producers down when consumers can't keep up is
+// "backpressure", and a blocking queue is how you get it.
+type BlockingQueue[T any] struct {
+	items     chan T
+	closed    chan struct{} // closed by Close, wakes up everyone waiting
+	closeOnce sync.Once
+}
+
+// NewBlockingQueue makes a queue holding up to size items.
+func NewBlockingQueue[T any](size int) *BlockingQueue[T] {
+	return &BlockingQueue[T]{
+		items:  make(chan T, size),
+		closed: make(chan struct{}),
+	}
+}
+
+// Put adds item, waiting while the queue is full.
+func (q *BlockingQueue[T]) Put(item T) error {
+	// Check first, otherwise the select below could still pick the
+	// send if there happens to be room.
+	select {
+	case <-q.closed:
+		return ErrQueueClosed
+	default:
+	}
+
+	select {
+	case q.items <- item:
+		return nil
+	case <-q.closed: // closed while we were waiting for room
+		return ErrQueueClosed
+	}
+}
+
+// Take removes the oldest item, waiting while the queue is empty.
+// ok is false once the queue is closed and empty.
+func (q *BlockingQueue[T]) Take() (T, bool) {
+	select {
+	case item := <-q.items:
+		return item, true
+	case <-q.closed:
+		// Closed, but there might be items left, hand those out first.
+		select {
+		case item := <-q.items:
+			return item, true
+		default:
+			var zero T
+			return zero, false
+		}
+	}
+}
+
+// Close stops new Puts and wakes up anyone waiting. Safe to call more than once.
+//
+// We never close q.items itself, that's what would make a racing Put panic.
+func (q *BlockingQueue[T]) Close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+}
+
+// Debounce returns a function that waits until it hasn't been called for d,
+// then runs fn once. Every call in the meantime restarts the wait.
+//
+// Think of a search box: don't query on every keystroke, query once the user
+// stops typing. Same as lodash's _.debounce in JS.
+//
+// fn runs on the timer's own goroutine, not the caller's.
+func Debounce(d time.Duration, fn func()) func() {
+	var mu sync.Mutex // the returned func can be called from many goroutines
+	var timer *time.Timer
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if timer == nil {
+			timer = time.AfterFunc(d, fn)
+			return
+		}
+		// Stop + Reset pushes the deadline back to d from now.
+		// If the timer already fired, fn already ran, Reset just schedules the next run.
+		timer.Stop()
+		timer.Reset(d)
+	}
+}
+
+// Throttle returns a function that runs fn at most once every d.
+// The first call runs right away, calls during the next d are dropped (not queued).
+//
+// Same as lodash's _.throttle in JS (the leading edge version).
+// Unlike Debounce, fn runs on the caller's goroutine.
+func Throttle(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var last time.Time // zero value, a long long time ago, so the first call always runs
+
+	return func() {
+		mu.Lock()
+		now := time.Now()
+		if now.Sub(last) < d {
+			mu.Unlock()
+			return // too soon, drop it
+		}
+		last = now
+		mu.Unlock() // don't hold the lock while fn runs, it could be slow
+
+		fn()
+	}
+}
+
+// Tee copies every value from in to both outputs, like the unix tee command.
+// Both outputs are closed once in is closed.
+//
+// The trade off: the outputs are unbuffered and each value has to reach
+// BOTH before Tee reads the next one. So the two consumers move in lockstep,
+// a slow one slows the other down, and one that stops reading blocks both.
+// That's on purpose, nothing gets dropped and memory can't grow without limit.
+// If you'd rather let a fast consumer run ahead, buffer the outputs or put a
+// BlockingQueue in front of the slow one.
+func Tee[T any](in <-chan T) (<-chan T, <-chan T) {
+	out1 := make(chan T)
+	out2 := make(chan T)
+
+	go func() {
+		defer close(out1)
+		defer close(out2)
+
+		for value := range in {
+			// Send to whichever is ready first, then the other.
+			// Setting a channel to nil turns off its case in the select,
+			// sending to a nil channel blocks forever so it's never picked.
+			a, b := out1, out2
+			for i := 0; i < 2; i++ {
+				select {
+				case a <- value:
+					a = nil
+				case b <- value:
+					b = nil
+				}
+			}
+		}
+	}()
+
+	return out1, out2
+}
+
+// roundRobin deals items from in to the workers in turn: 0, 1, 2, 0, 1, 2...
+// When in is closed it closes every worker channel, so workers can range over theirs.
+//
+// Fair distribution vs work stealing:
+//   - round robin (this): every worker gets the same NUMBER of items, and you
+//     know in advance which worker gets which. But if worker 0 gets a slow item,
+//     everything queued for worker 0 waits, even while the others sit idle.
+//     The sends are unbuffered, so it actually stalls the whole dispatcher.
+//   - one shared channel (go_3's stock workers): whoever is free takes the next
+//     item. Items spread by how fast each worker is, so nobody idles while there's
+//     work, but the order is random. Go's runtime does something similar with
+//     goroutines, idle threads "steal" work queued on busy ones.
+//
+// Round robin is good for spreading load evenly over identical backends
+// (it's what simple load balancers do), or when order per worker matters.
+func roundRobin(in <-chan string, workers []chan<- string) {
+	defer func() {
+		for _, worker := range workers {
+			close(worker)
+		}
+	}()
+
+	next := 0
+	for item := range in {
+		workers[next] <- item
+		next = (next + 1) % len(workers)
+	}
+}
+
+// ErrPoolClosed is returned by Submit after Close.
+var ErrPoolClosed = errors.New("pool closed")
+
+// Pool runs jobs on a set of worker goroutines, and the number of workers
+// can change while it runs. python's ThreadPoolExecutor can't do that.
+//
+// All workers take jobs from one shared channel (whoever is free, see roundRobin
+// above). Each worker also has its own stop channel, closing it stops
+// exactly that worker, and no other. That's how Resize picks who goes.
+type Pool struct {
+	jobs chan func()
+	quit chan struct{} // closed by Close, stops all workers
+
+	mu      sync.Mutex
+	workers []poolWorker // the running workers, Resize adds and removes at the end
+	closed  bool
+
+	running atomic.Int32 // a gauge, how many worker goroutines are alive right now
+}
+
+type poolWorker struct {
+	stop chan struct{} // we close it to tell the worker to stop
+	done chan struct{} // the worker closes it once it has stopped
+}
+
+// NewPool starts a pool with n workers.
+func NewPool(n int) *Pool {
+	p := &Pool{
+		jobs: make(chan func()), // unbuffered, Submit waits for a free worker (backpressure, like BlockingQueue)
+		quit: make(chan struct{}),
+	}
+	p.Resize(n)
+	return p
+}
+
+// Submit hands job to a free worker, waiting until one is free.
+func (p *Pool) Submit(job func()) error {
+	select {
+	case <-p.quit:
+		return ErrPoolClosed
+	default:
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-p.quit:
+		return ErrPoolClosed
+	}
+}
+
+// Workers is how many workers are running.
+func (p *Pool) Workers() int {
+	return int(p.running.Load())
+}
+
+// Resize starts or stops workers until there are n.
+//
+// Stopping never interrupts a job (Go can't kill a goroutine from outside anyway).
+// A stopped worker finishes the job it's on, then exits, and Resize waits
+// for that, so when it returns Workers() == n. A negative n is treated as 0.
+func (p *Pool) Resize(n int) {
+	n = max(n, 0) // p.workers[n:] panics on a negative n
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+
+	for len(p.workers) < n {
+		w := poolWorker{stop: make(chan struct{}), done: make(chan struct{})}
+		p.workers = append(p.workers, w)
+		p.running.Add(1)
+		go p.work(w)
+	}
+
+	if len(p.workers) > n {
+		extra := p.workers[n:]
+		p.workers = p.workers[:n]
+		for _, w := range extra {
+			close(w.stop)
+		}
+		for _, w := range extra {
+			<-w.done
+		}
+	}
+}
+
+// Close stops every worker once its current job is done, and waits for them.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+
+	close(p.quit)
+	for _, w := range p.workers {
+		<-w.done
+	}
+	p.workers = nil
+}
+
+func (p *Pool) work(w poolWorker) {
+	defer close(w.done)
+	defer p.running.Add(-1)
+
+	for {
+		// If a job and a stop are BOTH ready, select picks one at random.
+		// Check for stop on its own first, so a stopped worker doesn't grab another job.
+		select {
+		case <-w.stop:
+			return
+		case <-p.quit:
+			return
+		default:
+		}
+
+		select {
+		case <-w.stop:
+			return
+		case <-p.quit:
+			return
+		case job := <-p.jobs:
+			job()
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+//	go test -race go_12_concurrency_patterns.go go_12_concurrency_patterns_test.go
+//
+// -race matters most for this file, every helper here is shared between goroutines.
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBlockingQueueProducersAndConsumers(t *testing.T) {
+	const producers, perProducer = 4, 250
+	q := NewBlockingQueue[int](10) // much smaller than the total, so Put really has to wait
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := q.Put(p*perProducer + i); err != nil {
+					t.Errorf("Put: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	var consumers sync.WaitGroup
+	for c := 0; c < 3; c++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for {
+				item, ok := q.Take()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				seen[item]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	q.Close() // the consumers still drain what's left, then Take says ok == false
+	consumers.Wait()
+
+	if len(seen) != producers*perProducer {
+		t.Errorf("got %d different items, want %d", len(seen), producers*perProducer)
+	}
+	for item, count := range seen {
+		if count != 1 {
+			t.Errorf("item %d taken %d times", item, count)
+		}
+	}
+}
+
+func TestBlockingQueueClose(t *testing.T) {
+	q := NewBlockingQueue[string](1)
+	if err := q.Put("first"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue is full, so this Put waits until Close wakes it up.
+	blocked := make(chan error)
+	go func() { blocked <- q.Put("second") }()
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	q.Close() // twice is fine
+
+	if err := <-blocked; !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("waiting Put = %v, want ErrQueueClosed", err)
+	}
+	if err := q.Put("third"); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Put after Close = %v, want ErrQueueClosed", err)
+	}
+	if item, ok := q.Take(); item != "first" || !ok {
+		t.Errorf("Take = %q, %v, want what was left", item, ok)
+	}
+	if item, ok := q.Take(); item != "" || ok {
+		t.Errorf("Take on empty = %q, %v, want \"\", false", item, ok)
+	}
+
+	// And a Take waiting on an empty queue is woken up too.
+	empty := NewBlockingQueue[string](1)
+	done := make(chan bool)
+	go func() { _, ok := empty.Take(); done <- ok }()
+	time.Sleep(10 * time.Millisecond)
+	empty.Close()
+	if ok := <-done; ok {
+		t.Error("waiting Take got ok == true from an empty closed queue")
+	}
+}
+
+func TestDebounceRunsOnceAfterTheQuietPeriod(t *testing.T) {
+	var calls atomic.Int32
+	debounced := Debounce(50*time.Millisecond, func() { calls.Add(1) })
+
+	for i := 0; i < 20; i++ { // 20 calls over ~20ms, each one restarts the 50ms wait
+		debounced()
+		time.Sleep(time.Millisecond)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("fn ran %d times while calls were still coming in, want 0", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fn ran %d times after the quiet period, want exactly 1", got)
+	}
+
+	// A new burst after it fired gets its own run.
+	debounced()
+	debounced()
+	time.Sleep(150 * time.Millisecond)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("fn ran %d times after a second burst, want 2", got)
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	const every = 50 * time.Millisecond
+	var calls atomic.Int32
+	throttled := Throttle(every, func() { calls.Add(1) })
+
+	// Call it as fast as we can for a bit over 4 intervals: runs at ~0, 50, 100, 150, 200ms.
+	start := time.Now()
+	attempts := 0
+	for time.Since(start) < 4*every+every/2 {
+		throttled()
+		attempts++
+		time.Sleep(time.Millisecond)
+	}
+
+	// Sleeps can run long on a busy machine, so allow one either way.
+	if got := calls.Load(); got < 4 || got > 6 {
+		t.Errorf("fn ran %d times out of %d calls in %v, want ~5, once per %v", got, attempts, time.Since(start), every)
+	}
+	if attempts < 20 {
+		t.Errorf("only %d calls, the loop didn't call fast enough for the test to mean anything", attempts)
+	}
+}
+
+func TestTeeBothGetEverything(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+	}()
+
+	out1, out2 := Tee(in)
+	var got1, got2 []int
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for v := range out1 {
+			got1 = append(got1, v)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for v := range out2 {
+			got2 = append(got2, v)
+			if v%10 == 0 {
+				time.Sleep(time.Millisecond) // a slower consumer, still gets everything
+			}
+		}
+	}()
+	wg.Wait() // only returns if Tee closed both outputs
+
+	if len(got1) != 100 || !slices.Equal(got1, got2) {
+		t.Errorf("out1 got %d values, out2 got %d, want the same 100 in the same order", len(got1), len(got2))
+	}
+	if !slices.IsSorted(got1) {
+		t.Errorf("out1 = %v, want the order they went in", got1)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	in := make(chan string)
+	workers := make([]chan string, 3)
+	sendOnly := make([]chan<- string, 3)
+	for i := range workers {
+		workers[i] = make(chan string, 10)
+		sendOnly[i] = workers[i]
+	}
+
+	done := make(chan struct{})
+	go func() {
+		roundRobin(in, sendOnly)
+		close(done)
+	}()
+	for _, item := range []string{"a", "b", "c", "d", "e", "f"} {
+		in <- item
+	}
+	close(in)
+	<-done
+
+	want := [][]string{{"a", "d"}, {"b", "e"}, {"c", "f"}}
+	for i, worker := range workers {
+		var got []string
+		for item := range worker { // ends because roundRobin closed it
+			got = append(got, item)
+		}
+		if !slices.Equal(got, want[i]) {
+			t.Errorf("worker %d got %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestPoolResize(t *testing.T) {
+	pool := NewPool(2)
+	defer pool.Close()
+	if got := pool.Workers(); got != 2 {
+		t.Fatalf("NewPool(2) has %d workers", got)
+	}
+
+	// Keep the pool busy the whole time with a stream of jobs.
+	var ran atomic.Int32
+	stop := make(chan struct{})
+	var submitting sync.WaitGroup
+	submitting.Add(1)
+	go func() {
+		defer submitting.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			err := pool.Submit(func() {
+				time.Sleep(time.Millisecond)
+				ran.Add(1)
+			})
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, n := range []int{5, 1, 8, 3, 0, 4} {
+		pool.Resize(n)
+		if got := pool.Workers(); got != n {
+			t.Errorf("after Resize(%d): %d workers", n, got)
+		}
+		time.Sleep(5 * time.Millisecond) // let some jobs run at this size
+	}
+	pool.Resize(-3)
+	if got := pool.Workers(); got != 0 {
+		t.Errorf("after Resize(-3): %d workers, want 0", got)
+	}
+	pool.Resize(2) // something has to run the Submit that's waiting
+	close(stop)
+	submitting.Wait()
+
+	if ran.Load() == 0 {
+		t.Error("no jobs ran")
+	}
+}
+
+func TestPoolResizeDownLetsJobsFinish(t *testing.T) {
+	pool := NewPool(3)
+	release := make(chan struct{})
+	var finished atomic.Int32
+	for i := 0; i < 3; i++ {
+		if err := pool.Submit(func() {
+			<-release
+			finished.Add(1)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resized := make(chan struct{})
+	go func() {
+		pool.Resize(1) // two workers have to stop, but they're busy, so this waits
+		close(resized)
+	}()
+	select {
+	case <-resized:
+		t.Fatal("Resize returned while the workers it stopped were still running jobs")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-resized
+	// Resize only waits for the two it stopped, the one it kept may still be on its job.
+	if got := pool.Workers(); got != 1 || finished.Load() < 2 {
+		t.Errorf("%d workers, %d jobs finished, want 1 worker and the stopped workers' jobs done", got, finished.Load())
+	}
+
+	pool.Close()
+	if got := pool.Workers(); got != 0 || finished.Load() != 3 {
+		t.Errorf("after Close: %d workers, %d jobs finished, want 0 and every job done", got, finished.Load())
+	}
+	if err := pool.Submit(func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Close = %v, want ErrPoolClosed", err)
+	}
+	pool.Resize(5) // does nothing once closed
+	if got := pool.Workers(); got != 0 {
+		t.Errorf("Resize after Close: %d workers, want 0", got)
+	}
+}
+
+func TestRunAllKeepsPositions(t *testing.T) {
+	errSecond, errFourth := errors.New("second failed"), errors.New("fourth failed")
+	errs := RunAll(
+		func() error { time.Sleep(3 * time.Millisecond); return nil },
+		func() error { time.Sleep(2 * time.Millisecond); return errSecond },
+		func() error { return nil },
+		func() error { return errFourth }, // finishes first, still lands in slot 3
+	)
+	want := []error{nil, errSecond, nil, errFourth}
+	if !slices.Equal(errs, want) {
+		t.Errorf("RunAll = %v, want %v", errs, want)
+	}
+
+	if errs := RunAll(); len(errs) != 0 {
+		t.Errorf("RunAll() = %v, want an empty slice", errs)
+	}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// User is the same struct from go_1_intro.go.
+type User struct {
+	Name     string
+	Password string
+}
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// LRU cache
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Pretend this is a slow database lookup.
+	lookups := 0
+	loadUser := func(name string) User {
+		lookups++
+		return User{Name: name, Password: "hunter2"}
+	}
+
+	users := NewLRUCache[string, User](2)
+	getUser := func(name string) User {
+		if user, ok := users.Get(name); ok {
+			return user
+		}
+		user := loadUser(name)
+		users.Put(name, user)
+		return user
+	}
+
+	getUser("alice")     // miss, load
+	getUser("bob")       // miss, load
+	getUser("alice")     // hit, alice is now the most recently used
+	getUser("carol")     // miss, load, cache is full so bob (least recently used) is evicted
+	fmt.Println(lookups) // 3
+
+	_, ok := users.Get("bob")
+	fmt.Println(ok) // false, evicted
+	_, ok = users.Get("alice")
+	fmt.Println(ok)           // true, the Get above saved it
+	fmt.Println(users.Keys()) // [alice carol], most recent first
+
+	// A zero sized cache holds nothing, like RingBuffer in go_5_generics.go. Every Get misses.
+	nothing := NewLRUCache[string, User](0)
+	nothing.Put("alice", User{Name: "alice"})
+	fmt.Println(nothing.Len()) // 0
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// TTL cache
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Sessions that expire after 50ms, swept every 10ms.
+	sessions := NewTTLCache[string, User](50*time.Millisecond, 10*time.Millisecond)
+	sessions.Put("token-123", User{Name: "alice"})
+
+	session, ok := sessions.Get("token-123")
+	fmt.Println(session.Name, ok) // alice true
+	fmt.Println(sessions.Len())   // 1
+
+	time.Sleep(80 * time.Millisecond)
+	_, ok = sessions.Get("token-123")
+	fmt.Println(ok)             // false, expired
+	fmt.Println(sessions.Len()) // 0, and the sweeper already threw it away
+
+	sessions.Close()               // returns once the sweeper goroutine has exited
+	sessions.Close()               // safe to call twice
+	fmt.Println("sweeper stopped") // sweeper stopped
+
+	// sweepEvery 0 means no sweeper (instead of a NewTicker panic). Expired entries
+	// are still hidden from Get, they just aren't thrown away.
+	unswept := NewTTLCache[string, User](time.Millisecond, 0)
+	unswept.Put("token-456", User{Name: "bob"})
+	time.Sleep(5 * time.Millisecond)
+	_, ok = unswept.Get("token-456")
+	fmt.Println(ok, unswept.Len()) // false 1
+	unswept.Close()                // nothing to stop, returns right away
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Memoize
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Plain recursive fibonacci recomputes the same numbers over and over,
+	// fib(50) would make ~40 billion calls. Memoized, each n is computed once.
+	fibCalls := 0
+	var fib func(n int) int // declared first so the func below can call itself
+	fib = Memoize(func(n int) int {
+		fibCalls++
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2) // calls the MEMOIZED fib
+	})
+	fmt.Println(fib(50))  // 12586269025
+	fmt.Println(fibCalls) // 51, once for each of 0..50
+	fib(50)
+	fmt.Println(fibCalls) // 51, all cached
+
+	// 10 goroutines asking for the same slow thing at once, still computed once.
+	var wg sync.WaitGroup
+	var computed int
+	var computedMu sync.Mutex
+	slowSquare := Memoize(func(n int) int {
+		computedMu.Lock()
+		computed++
+		computedMu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		return n * n
+	})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slowSquare(7)
+		}()
+	}
+	wg.Wait()
+	fmt.Println(slowSquare(7), computed) // 49 1
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Duplicate call suppression (singleflight)
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// 10 requests come in for alice's profile at the same moment, the cache is empty.
+	// Without this, that's 10 identical database queries (a "thundering herd").
+	var fetches int
+	var fetchesMu sync.Mutex
+	fetchUser := func(name string) (User, error) {
+		fetchesMu.Lock()
+		fetches++
+		fetchesMu.Unlock()
+		time.Sleep(20 * time.Millisecond) // slow database
+		return User{Name: name}, nil
+	}
+
+	var group FlightGroup[string, User]
+	var callers sync.WaitGroup
+	var sharedCount int
+	var sharedMu sync.Mutex
+	for i := 0; i < 10; i++ {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+			user, err, shared := group.Do("alice", func() (User, error) {
+				return fetchUser("alice")
+			})
+			if err != nil || user.Name != "alice" {
+				fmt.Println("bad result", user, err)
+			}
+			if shared {
+				sharedMu.Lock()
+				sharedCount++
+				sharedMu.Unlock()
+			}
+		}()
+	}
+	callers.Wait()
+	fmt.Println(fetches)     // 1, one query for all 10 callers
+	fmt.Println(sharedCount) // 10, everyone got the same result (the caller that ran it too)
+
+	// Once it's finished, it's forgotten. The next call fetches again.
+	_, _, _ = group.Do("alice", func() (User, error) { return fetchUser("alice") })
+	fmt.Println(fetches) // 2
+
+	// fn panics while someone is waiting on it.
+	running, release := make(chan struct{}), make(chan struct{})
+	leaderDone := make(chan any)
+	go func() {
+		defer func() { leaderDone <- recover() }()
+		_, _, _ = group.Do("bob", func() (User, error) {
+			close(running)
+			<-release
+			panic("db driver bug")
+		})
+	}()
+	<-running
+	waiterDone := make(chan error)
+	go func() {
+		_, err, _ := group.Do("bob", func() (User, error) { return fetchUser("bob") })
+		waiterDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let the waiter join in
+	close(release)
+	fmt.Println(<-leaderDone) // db driver bug, the caller that ran fn gets the panic
+	fmt.Println(<-waiterDone) // FlightGroup: fn panicked: db driver bug, instead of waiting forever
+
+	user, err, _ := group.Do("bob", func() (User, error) { return fetchUser("bob") })
+	fmt.Println(user.Name, err) // bob <nil>, the key was cleaned up
+}
+
+// LRUCache keeps the capacity most recently used entries, python's @functools.lru_cache
+// but as a data structure you Get and Put into yourself.
+//
+// Two structures working together, both O(1):
+//   - a map, to find an entry by key
+//   - a container/list (go_23_heap.go), ordered by recency. Front is the most
+//     recently used, Back is next in line to be evicted.
+//
+// The map points at list elements, so "move to front" doesn't have to search the list.
+// That's the case where a linked list beats a slice.
+//
+// Not goroutine safe, wrap it in a sync.Mutex if you share it.
+type LRUCache[K comparable, V any] struct {
+	capacity int
+	order    *list.List          // of *lruEntry[K, V]
+	entries  map[K]*list.Element // key -> its element in order
+}
+
+// lruEntry is what's stored in the list. It needs the key too, so when we evict
+// the Back element we know which map entry to delete.
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRUCache makes a cache holding up to capacity entries.
+// A capacity of 0 (or less) makes a cache that never stores anything.
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	capacity = max(capacity, 0) // make panics on a negative size
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[K]*list.Element, capacity),
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// Put adds or updates key, evicting the least recently used entry if full.
+func (c *LRUCache[K, V]) Put(key K, value V) {
+	if c.capacity == 0 {
+		return // nowhere to put it, and no Back to evict (it'd be nil)
+	}
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+// Len is how many entries are cached.
+func (c *LRUCache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Keys lists the cached keys, most recently used first. Doesn't change the order.
+func (c *LRUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.order.Len())
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+// TTLCache forgets entries ttl after they were put in. Think login sessions,
+// or python's cachetools.TTLCache.
+//
+// A background goroutine sweeps out expired entries every so often, so
+// entries nobody asks for again don't pile up in memory forever.
+// Always Close it when you're done, or that goroutine leaks.
+//
+// The sweeper and Get run at the same time, so everything goes through mu.
+// Get also checks the expiry itself, an entry can expire between two sweeps,
+// and we must never hand it out just because the sweeper hasn't got to it yet.
+type TTLCache[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[K]ttlEntry[V]
+
+	stop      chan struct{} // closed by Close, tells the sweeper to exit
+	done      chan struct{} // closed by the sweeper on its way out
+	closeOnce sync.Once
+}
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewTTLCache makes a cache whose entries live for ttl, and starts a
+// sweeper goroutine that runs every sweepEvery.
+//
+// A sweepEvery of 0 (or less) turns sweeping off, time.NewTicker would panic on it.
+// Get still never returns an expired entry, they just stay in memory until
+// they're Put over. Close is still fine to call.
+func NewTTLCache[K comparable, V any](ttl, sweepEvery time.Duration) *TTLCache[K, V] {
+	c := &TTLCache[K, V]{
+		ttl:     ttl,
+		entries: make(map[K]ttlEntry[V]),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if sweepEvery <= 0 {
+		close(c.done) // no sweeper to wait for
+		return c
+	}
+	go c.sweep(sweepEvery)
+	return c
+}
+
+// Put adds or replaces key, its ttl starts now.
+func (c *TTLCache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = ttlEntry[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Get returns the value for key, if it's there and hasn't expired.
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Len is how many entries are stored, including expired ones not swept yet.
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Close stops the sweeper and waits for it to exit. Safe to call more than once.
+func (c *TTLCache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+}
+
+func (c *TTLCache[K, V]) sweep(every time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for key, entry := range c.entries {
+				if now.After(entry.expiresAt) {
+					delete(c.entries, key) // deleting while ranging over a map is allowed in Go
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Memoize wraps fn so each distinct key is only computed once, later calls
+// get the cached result. python's @functools.cache.
+//
+// The map is guarded by a mutex, but we don't hold it while fn runs:
+// that would make every call wait for every other, and a recursive fn
+// (like fib above) would deadlock waiting on itself.
+// Instead each key gets its own sync.Once, so 10 goroutines asking for
+// the same key at the same time run fn once and the other 9 wait for it.
+//
+// The cache never shrinks, use the LRU or TTL cache above if that matters.
+func Memoize[K comparable, V any](fn func(K) V) func(K) V {
+	var mu sync.Mutex
+	cache := make(map[K]*memoEntry[V])
+
+	return func(key K) V {
+		mu.Lock()
+		entry, ok := cache[key]
+		if !ok {
+			entry = &memoEntry[V]{}
+			cache[key] = entry
+		}
+		mu.Unlock()
+
+		entry.once.Do(func() {
+			entry.value = fn(key)
+		})
+		return entry.value
+	}
+}
+
+type memoEntry[V any] struct {
+	once  sync.Once
+	value V
+}
+
+// FlightGroup merges identical calls that are running at the same time.
+// The first caller for a key runs fn, anyone else asking for that key
+// while it's running waits and gets the same result.
+//
+// It's golang.org/x/sync/singleflight, cut down, with generics.
+//
+// How is this different from Memoize? Memoize remembers results forever.
+// FlightGroup only merges calls that OVERLAP, as soon as fn returns the key
+// is forgotten, so the next call gets fresh data. Use it in front of a
+// cache, to stop 10 cache misses turning into 10 database queries.
+//
+// The zero value is ready to use.
+type FlightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flight[V] // calls in progress
+}
+
+// flight is one call in progress, the waiters block on wg.
+type flight[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+	dups  int // how many other callers joined in
+}
+
+// Do runs fn for key, or waits for the call already running for key.
+// shared is true if the result went to more than one caller.
+func (g *FlightGroup[K, V]) Do(key K, fn func() (V, error)) (value V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flight[V])
+	}
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		call.wg.Wait() // someone else is already on it
+		return call.value, call.err, true
+	}
+	call := &flight[V]{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// If fn panics the call still has to finish, or the waiters block on wg
+	// forever and the key is stuck so every later call for it blocks too.
+	// The waiters get an error, the caller that ran fn gets the panic back,
+	// same as calling fn without a FlightGroup.
+	var panicked any
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = r
+				call.err = fmt.Errorf("FlightGroup: fn panicked: %v", r)
+			}
+		}()
+		call.value, call.err = fn()
+	}()
+	call.wg.Done() // wake up the waiters, value and err are set before this so they see them
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	shared = call.dups > 0
+	g.mu.Unlock()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+	return call.value, call.err, shared
+}
@@ -0,0 +1,305 @@
+package main
+
+//	go test -race go_13_caching.go go_13_caching_test.go
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEvictionOrder(t *testing.T) {
+	cache := NewLRUCache[string, int](3)
+	for i, key := range []string{"a", "b", "c"} {
+		cache.Put(key, i)
+	}
+	cache.Put("d", 3) // full, "a" is the oldest
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("a is still cached, it should have been evicted first")
+	}
+	if want := []string{"d", "c", "b"}; !slices.Equal(cache.Keys(), want) {
+		t.Errorf("Keys = %q, want %q", cache.Keys(), want)
+	}
+
+	cache.Put("e", 4)
+	cache.Put("f", 5)
+	if want := []string{"f", "e", "d"}; !slices.Equal(cache.Keys(), want) || cache.Len() != 3 {
+		t.Errorf("Keys = %q, Len = %d, want %q", cache.Keys(), cache.Len(), want)
+	}
+}
+
+func TestLRUCacheGetAndPutRefresh(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if value, ok := cache.Get("a"); value != 1 || !ok { // a is now the most recent
+		t.Fatalf("Get(a) = %d, %v", value, ok)
+	}
+	cache.Put("c", 3) // so b goes, not a
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b is still cached, Get(a) should have made b the oldest")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("a was evicted even though it was just used")
+	}
+
+	cache.Put("c", 30) // updating counts as a use too, and doesn't grow the cache
+	cache.Put("d", 4)
+	if value, ok := cache.Get("c"); value != 30 || !ok || cache.Len() != 2 {
+		t.Errorf("Get(c) = %d, %v, Len = %d, want 30, true, 2", value, ok, cache.Len())
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("a is still cached, it was the oldest after Put(c)")
+	}
+}
+
+func TestLRUCacheZeroCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		cache := NewLRUCache[string, int](capacity)
+		cache.Put("a", 1)
+		if _, ok := cache.Get("a"); ok || cache.Len() != 0 {
+			t.Errorf("capacity %d: cached something, want nothing stored", capacity)
+		}
+	}
+}
+
+func TestTTLCacheExpiry(t *testing.T) {
+	cache := NewTTLCache[string, string](20*time.Millisecond, time.Hour) // the sweeper won't get to it
+	defer cache.Close()
+
+	cache.Put("alice", "session-1")
+	if value, ok := cache.Get("alice"); value != "session-1" || !ok {
+		t.Fatalf("Get right away = %q, %v", value, ok)
+	}
+	time.Sleep(40 * time.Millisecond)
+	// Still in the map, but Get checks the time itself.
+	if value, ok := cache.Get("alice"); value != "" || ok {
+		t.Errorf("Get after expiry = %q, %v, want it gone", value, ok)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Len = %d, want 1, nothing has swept it yet", cache.Len())
+	}
+
+	cache.Put("alice", "session-2") // putting again starts a new ttl
+	if value, ok := cache.Get("alice"); value != "session-2" || !ok {
+		t.Errorf("Get after Put again = %q, %v", value, ok)
+	}
+}
+
+func TestTTLCacheSweeper(t *testing.T) {
+	cache := NewTTLCache[string, int](10*time.Millisecond, 5*time.Millisecond)
+	for i, key := range []string{"a", "b", "c"} {
+		cache.Put(key, i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d a second later, want the sweeper to have removed everything", cache.Len())
+	}
+
+	cache.Close()
+	select {
+	case <-cache.done:
+	default:
+		t.Fatal("Close returned but the sweeper goroutine is still running")
+	}
+	cache.Close() // twice is fine
+
+	// Nothing sweeps once it's closed.
+	cache.Put("d", 4)
+	time.Sleep(30 * time.Millisecond)
+	if cache.Len() != 1 {
+		t.Errorf("Len = %d after Close, want the expired entry still there", cache.Len())
+	}
+}
+
+func TestTTLCacheNoSweeping(t *testing.T) {
+	for _, sweepEvery := range []time.Duration{0, -time.Second} {
+		cache := NewTTLCache[string, int](time.Millisecond, sweepEvery) // used to panic in time.NewTicker
+		cache.Put("a", 1)
+		time.Sleep(5 * time.Millisecond)
+		if _, ok := cache.Get("a"); ok || cache.Len() != 1 {
+			t.Errorf("sweepEvery %v: Get ok = %v, Len = %d, want expired but not swept", sweepEvery, ok, cache.Len())
+		}
+		cache.Close() // mustn't block waiting for a sweeper that never started
+	}
+}
+
+func TestMemoizeOncePerKeyConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[int]int{}
+	square := Memoize(func(n int) int {
+		mu.Lock()
+		calls[n]++
+		mu.Unlock()
+		time.Sleep(time.Millisecond) // long enough for the goroutines to pile up on the same key
+		return n * n
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 50; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 5; n++ {
+				if got := square(n); got != n*n {
+					t.Errorf("square(%d) = %d", n, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for n := 0; n < 5; n++ {
+		if calls[n] != 1 {
+			t.Errorf("fn(%d) ran %d times, want once", n, calls[n])
+		}
+	}
+}
+
+func TestMemoizeRecursive(t *testing.T) {
+	var calls atomic.Int32
+	var fib func(int) int
+	fib = Memoize(func(n int) int {
+		calls.Add(1)
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	})
+	if got := fib(50); got != 12586269025 {
+		t.Errorf("fib(50) = %d", got)
+	}
+	if calls.Load() != 51 { // 0 through 50, once each, instead of billions
+		t.Errorf("fn ran %d times, want 51", calls.Load())
+	}
+}
+
+// waitForDups waits until n callers have joined key's call in progress.
+func waitForDups(t *testing.T, g *FlightGroup[string, string], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call := g.calls[key]
+		joined := call != nil && call.dups == n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d callers never joined the call for %s", n, key)
+}
+
+func TestFlightGroupRunsOnceForTenCallers(t *testing.T) {
+	var group FlightGroup[string, string]
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (string, error) {
+		fetches.Add(1)
+		<-release // hold the call open until everyone has joined
+		return "Alice", nil
+	}
+
+	results := make([]string, 10)
+	var sharedCount atomic.Int32
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err, shared := group.Do("alice", fetch)
+			if err != nil {
+				t.Error(err)
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+			results[i] = value
+		}()
+	}
+	waitForDups(t, &group, "alice", 9)
+	close(release)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetch ran %d times for 10 simultaneous callers, want once", got)
+	}
+	for i, result := range results {
+		if result != "Alice" {
+			t.Errorf("caller %d got %q", i, result)
+		}
+	}
+	if sharedCount.Load() != 10 {
+		t.Errorf("%d callers saw shared == true, want all 10", sharedCount.Load())
+	}
+
+	// The call is over, so the next one runs fn again, unlike Memoize.
+	if _, _, shared := group.Do("alice", fetch); shared || fetches.Load() != 2 {
+		t.Errorf("later call: shared = %v, %d fetches, want a fresh call", shared, fetches.Load())
+	}
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	var group FlightGroup[string, string]
+	release := make(chan struct{})
+
+	waiterErr := make(chan error)
+	leaderPanic := make(chan any)
+	go func() {
+		defer func() { leaderPanic <- recover() }()
+		group.Do("bob", func() (string, error) {
+			<-release
+			panic("database on fire")
+		})
+	}()
+	// Don't start the waiter until the leader's call exists, otherwise it might become the leader.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		group.mu.Lock()
+		started := group.calls["bob"] != nil
+		group.mu.Unlock()
+		if started || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	go func() {
+		_, err, _ := group.Do("bob", func() (string, error) { return "never runs", nil })
+		waiterErr <- err
+	}()
+	waitForDups(t, &group, "bob", 1)
+	close(release)
+
+	if recovered := <-leaderPanic; recovered != "database on fire" {
+		t.Errorf("leader recovered %v, want the panic passed on", recovered)
+	}
+	if err := <-waiterErr; err == nil || !strings.Contains(err.Error(), "database on fire") {
+		t.Errorf("waiter err = %v, want an error mentioning the panic", err)
+	}
+
+	// And the key isn't stuck, the next call runs normally.
+	value, err, _ := group.Do("bob", func() (string, error) { return "Bob", nil })
+	if value != "Bob" || err != nil {
+		t.Errorf("after the panic: Do = %q, %v, want Bob, nil", value, err)
+	}
+}
+
+func TestFlightGroupErrorsAreShared(t *testing.T) {
+	var group FlightGroup[string, string]
+	errDown := errors.New("database down")
+	_, err, shared := group.Do("carol", func() (string, error) { return "", errDown })
+	if !errors.Is(err, errDown) || shared {
+		t.Errorf("Do = %v, shared %v, want the error and shared false for a lone caller", err, shared)
+	}
+}
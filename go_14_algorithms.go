@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Classic interview algorithms, built from nothing but maps and slices.
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Graphs: BFS and DFS
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	//
+	//	alice --- bob --- dave
+	//	  |        |
+	//	carol -----+      erin (on her own)
+	//
+	// carol -> alice is a cycle, without a visited set we'd go round forever.
+	friends := graph{
+		"alice": {"bob", "carol"},
+		"bob":   {"alice", "carol", "dave"},
+		"carol": {"alice", "bob"},
+		"dave":  {"bob"},
+		"erin":  {},
+	}
+
+	fmt.Println(friends.bfs("alice")) // [alice bob carol dave], nearest first
+	fmt.Println(friends.dfs("alice")) // [alice bob carol dave], bob's neighbors before carol
+	fmt.Println(friends.dfs("dave"))  // [dave bob alice carol], as deep as it can before backing up
+	fmt.Println(friends.bfs("dave"))  // [dave bob alice carol]
+	fmt.Println(friends.bfs("erin"))  // [erin], nobody else is reachable
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Trie
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	words := NewTrie()
+	for _, word := range []string{"car", "cart", "café", "日本語"} {
+		words.Insert(word)
+	}
+
+	fmt.Println(words.Contains("car"), words.Contains("cart"))  // true true
+	fmt.Println(words.Contains("ca"), words.HasPrefix("ca"))    // false true, a prefix but not a word
+	fmt.Println(words.HasPrefix("cars"))                        // false
+	fmt.Println(words.Contains("café"), words.HasPrefix("caf")) // true true
+	fmt.Println(words.Contains("cafe"))                         // false, é isn't e
+	fmt.Println(words.HasPrefix("日本"), words.Contains("日本"))    // true false
+	fmt.Println(words.HasPrefix(""))                            // true, every word starts with ""
+	fmt.Println(NewTrie().HasPrefix(""))                        // false, but there aren't any words
+}
+
+// graph is an "adjacency list": each node, and the nodes it links to.
+// Python would be a dict of lists, {"alice": ["bob", "carol"]}.
+//
+// It's a named map type so it can have methods, like ByYear in go_24_sort.go.
+// Neighbors are a slice, not a set, so the visiting order is predictable.
+// Looping over a map would give a random order every run.
+type graph map[string][]string
+
+// bfs is breadth first search, it visits start, then all of start's
+// neighbors, then THEIR neighbors... in rings moving outwards.
+// It finds the fewest hops from start to anything, "friends of friends".
+//
+// Uses a queue (a slice we take from the front of), each node visited once.
+func (g graph) bfs(start string) []string {
+	visited := map[string]bool{start: true} // the quick way to write a set, go_5_generics.go's Set is the fancy one
+	queue := []string{start}
+	var order []string
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:] // pop from the front, like python's deque.popleft()
+		order = append(order, node)
+
+		for _, neighbor := range g[node] {
+			if !visited[neighbor] { // missing keys give false, no "in" check needed
+				visited[neighbor] = true // mark when ADDED to the queue, so it can't be queued twice
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return order
+}
+
+// dfs is depth first search, it follows one path as far as it goes,
+// then backs up and tries the next. Good for "is there any path",
+// finding cycles, or walking a directory tree.
+//
+// Recursion does the "backing up" for us, the call stack is the stack.
+// For a graph with a million nodes in a line, use a slice as the stack instead,
+// Go's stacks grow, but not without limit.
+func (g graph) dfs(start string) []string {
+	visited := map[string]bool{}
+	var order []string
+
+	// A closure can't call itself until it has a name, declare first.
+	var visit func(node string)
+	visit = func(node string) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		order = append(order, node)
+		for _, neighbor := range g[node] {
+			visit(neighbor)
+		}
+	}
+
+	visit(start)
+	return order
+}
+
+// Trie (say "try") stores words letter by letter, sharing prefixes:
+//
+//	root
+//	 └ c ─ a ─ r*─ t*
+//	       └ f ─ é*
+//
+// (* = a word ends here). Checking a prefix costs one step per letter,
+// no matter how many words are stored. It's how autocomplete works.
+//
+// Each node's children are keyed by rune, not byte. "é" is 2 bytes and "日"
+// is 3 (go_19_runes.go), keyed by byte we'd split them into nonsense half
+// characters. Ranging over a string hands us runes already.
+type Trie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	word     bool // does a word end here? "car" is a word, "ca" isn't
+}
+
+// NewTrie makes an empty Trie.
+func NewTrie() *Trie {
+	return &Trie{root: newTrieNode()}
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: map[rune]*trieNode{}}
+}
+
+// Insert adds word to the trie.
+func (t *Trie) Insert(word string) {
+	node := t.root
+	for _, letter := range word { // letter is a rune
+		child, ok := node.children[letter]
+		if !ok {
+			child = newTrieNode()
+			node.children[letter] = child
+		}
+		node = child
+	}
+	node.word = true
+}
+
+// Contains is true if word was inserted, not just a prefix of one.
+func (t *Trie) Contains(word string) bool {
+	node := t.find(word)
+	return node != nil && node.word
+}
+
+// HasPrefix is true if any inserted word starts with prefix.
+func (t *Trie) HasPrefix(prefix string) bool {
+	node := t.find(prefix)
+	// Nodes only get made by Insert, so any node below the root has a word
+	// under it. The root is there even when the trie is empty.
+	if node == t.root {
+		return len(node.children) > 0 || node.word // node.word if "" itself was inserted
+	}
+	return node != nil
+}
+
+// find walks down the letters of s, nil if we fall off the tree.
+func (t *Trie) find(s string) *trieNode {
+	node := t.root
+	for _, letter := range s {
+		node = node.children[letter]
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
@@ -0,0 +1,143 @@
+package main
+
+//	go test go_14_algorithms.go go_14_algorithms_test.go
+
+import (
+	"slices"
+	"testing"
+)
+
+// friends is the graph from main, alice, bob and carol make a cycle.
+//
+//	alice --- bob --- dave
+//	  |        |
+//	carol -----+      erin
+var friends = graph{
+	"alice": {"bob", "carol"},
+	"bob":   {"alice", "carol", "dave"},
+	"carol": {"alice", "bob"},
+	"dave":  {"bob"},
+	"erin":  {},
+}
+
+func TestTraversal(t *testing.T) {
+	tests := []struct {
+		name  string
+		walk  func(string) []string
+		start string
+		want  []string
+	}{
+		{"bfs from alice", friends.bfs, "alice", []string{"alice", "bob", "carol", "dave"}},
+		{"bfs from dave", friends.bfs, "dave", []string{"dave", "bob", "alice", "carol"}},
+		{"bfs from erin", friends.bfs, "erin", []string{"erin"}},
+		{"dfs from alice", friends.dfs, "alice", []string{"alice", "bob", "carol", "dave"}},
+		{"dfs from dave", friends.dfs, "dave", []string{"dave", "bob", "alice", "carol"}},
+		{"dfs from carol", friends.dfs, "carol", []string{"carol", "alice", "bob", "dave"}},
+		{"dfs from erin", friends.dfs, "erin", []string{"erin"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.walk(test.start); !slices.Equal(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTraversalVisitsEachNodeOnce(t *testing.T) {
+	// A tighter cycle: a -> b -> c -> a, and every node links to itself too.
+	loop := graph{
+		"a": {"a", "b"},
+		"b": {"b", "c"},
+		"c": {"c", "a"},
+	}
+	for name, walk := range map[string]func(string) []string{"bfs": loop.bfs, "dfs": loop.dfs} {
+		got := walk("a")
+		if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+			t.Errorf("%s = %q, want %q, each node once", name, got, want)
+		}
+	}
+
+	// A node nobody listed as a key is fine, g[node] is just nil.
+	if got := (graph{"a": {"ghost"}}).bfs("a"); !slices.Equal(got, []string{"a", "ghost"}) {
+		t.Errorf("bfs with a missing node = %q", got)
+	}
+}
+
+func TestBFSFindsTheShortestPathFirst(t *testing.T) {
+	// a reaches d in 1 hop directly, or 3 hops the long way round.
+	// DFS happens to go the long way, BFS always finds d in the first ring.
+	g := graph{
+		"a": {"b", "d"},
+		"b": {"c"},
+		"c": {"d"},
+	}
+	if got, want := g.bfs("a"), []string{"a", "b", "d", "c"}; !slices.Equal(got, want) {
+		t.Errorf("bfs = %q, want %q", got, want)
+	}
+	if got, want := g.dfs("a"), []string{"a", "b", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("dfs = %q, want %q", got, want)
+	}
+}
+
+func TestTrie(t *testing.T) {
+	words := NewTrie()
+	for _, word := range []string{"car", "cart", "café", "日本語", "🐹gopher"} {
+		words.Insert(word)
+	}
+
+	tests := []struct {
+		s                        string
+		wantContains, wantPrefix bool
+	}{
+		{"car", true, true},
+		{"cart", true, true},
+		{"ca", false, true}, // a prefix, but nobody inserted "ca"
+		{"c", false, true},
+		{"cars", false, false},
+		{"café", true, true},
+		{"caf", false, true},
+		{"cafe", false, false}, // é isn't e
+		{"日本語", true, true},
+		{"日本", false, true},
+		{"日", false, true},
+		{"本", false, false}, // in the middle of a word isn't a prefix
+		{"🐹", false, true},
+		{"🐹gopher", true, true},
+		{"", false, true}, // every word starts with ""
+	}
+	for _, test := range tests {
+		if got := words.Contains(test.s); got != test.wantContains {
+			t.Errorf("Contains(%q) = %v, want %v", test.s, got, test.wantContains)
+		}
+		if got := words.HasPrefix(test.s); got != test.wantPrefix {
+			t.Errorf("HasPrefix(%q) = %v, want %v", test.s, got, test.wantPrefix)
+		}
+	}
+}
+
+func TestTrieKeysByRune(t *testing.T) {
+	words := NewTrie()
+	words.Insert("日本語")
+	// 3 runes, 9 bytes. Keyed by rune that's 3 levels under the root, not 9.
+	depth := 0
+	for node := words.root; len(node.children) > 0; depth++ {
+		for _, child := range node.children {
+			node = child
+		}
+	}
+	if depth != 3 {
+		t.Errorf("日本語 is %d nodes deep, want 3, one per rune", depth)
+	}
+}
+
+func TestTrieEmpty(t *testing.T) {
+	empty := NewTrie()
+	if empty.HasPrefix("") || empty.Contains("") || empty.HasPrefix("a") {
+		t.Error("an empty trie should have no words and no prefixes, not even \"\"")
+	}
+	empty.Insert("")
+	if !empty.Contains("") || !empty.HasPrefix("") || empty.HasPrefix("a") {
+		t.Error("after Insert(\"\"), \"\" is both a word and a prefix, and nothing else is")
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Below http there's plain TCP and UDP, the net package.
+// python: the socket module / socketserver, JS: node's net and dgram.
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// TCP
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Port 0 means "pick any free port", Addr tells us which.
+	server, err := ListenLines("127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Try it by hand while it's up: nc 127.0.0.1 <port>, then type PING
+	conn, err := net.Dial("tcp", server.Addr())
+	if err != nil {
+		log.Fatal(err)
+	}
+	replies := bufio.NewScanner(conn)
+	for _, command := range []string{"PING", "ECHO hello there", "UPPER shout", "DANCE", "QUIT"} {
+		fmt.Fprintln(conn, command) // a conn is an io.Writer, Fprintln adds the \n the server splits on
+		replies.Scan()
+		fmt.Println(replies.Text())
+	}
+	// PONG
+	// hello there
+	// SHOUT
+	// ERR unknown command "DANCE"
+	// BYE
+	fmt.Println(replies.Scan()) // false, the server hung up after QUIT
+	conn.Close()
+
+	// A client that never says QUIT, Close still gets it off the server.
+	idle, err := net.Dial("tcp", server.Addr())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintln(idle, "PING")
+	bufio.NewScanner(idle).Scan() // wait for PONG, so the server has surely seen this connection
+	fmt.Println(server.Close())   // <nil>, returns once every connection goroutine is done
+	_, err = net.Dial("tcp", server.Addr())
+	fmt.Println(err != nil) // true, nobody's listening anymore
+	idle.Close()
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// UDP
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	echoed := make(chan struct{})
+	go func() {
+		defer close(echoed)
+		udpEcho(packetConn)
+	}()
+
+	// "Dial" for UDP doesn't connect to anything, nothing is sent until Write.
+	// It just remembers the address, so Write/Read work like TCP.
+	client, err := net.Dial("udp", packetConn.LocalAddr().String())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Write([]byte("hello over udp")); err != nil {
+		log.Fatal(err)
+	}
+	// The packet, or the reply, could be lost, and nobody would tell us.
+	// ALWAYS set a deadline on UDP reads, or a lost packet means waiting forever.
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	reply := make([]byte, 1500)
+	n, err := client.Read(reply)
+	fmt.Println(string(reply[:n]), err) // hello over udp <nil>
+
+	packetConn.Close() // ReadFrom in udpEcho returns an error, and it stops
+	<-echoed
+	fmt.Println("udp echo stopped") // udp echo stopped
+}
+
+// udpEcho sends every datagram it gets straight back to whoever sent it,
+// until conn is closed.
+//
+// UDP vs TCP:
+//   - no connection. No handshake, no Accept, no per client goroutine. One
+//     socket gets packets from everyone, ReadFrom says who each one came from.
+//   - packets, not a stream. One WriteTo is one ReadFrom on the other side,
+//     never glued together or split up (unlike TCP, no bufio.Scanner needed).
+//   - lossy and unordered. Packets can go missing, show up twice, or
+//     arrive out of order, and nothing retries or tells you. That's the price for being fast.
+//
+// Good for DNS, games, video calls, metrics (statsd), where a late packet is
+// useless anyway, or the app handles retries itself.
+func udpEcho(conn net.PacketConn) {
+	// One buffer per packet. A packet bigger than the buffer gets cut off,
+	// 1500 bytes is a typical network's max (MTU), so bigger risks getting dropped anyway.
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Println("udp read:", err)
+			}
+			return
+		}
+		if _, err := conn.WriteTo(buf[:n], from); err != nil {
+			log.Println("udp write:", err) // no connection to drop, just carry on with the next packet
+		}
+	}
+}
+
+// LineServer is a TCP server speaking a tiny line based protocol,
+// one command per line, one reply per line:
+//
+//	PING        -> PONG
+//	ECHO text   -> text
+//	UPPER text  -> TEXT
+//	QUIT        -> BYE, and hang up
+//
+// Redis and SMTP work the same way, text commands split on newlines.
+//
+// Each connection gets its own goroutine, like python's ThreadingTCPServer, but
+// goroutines are cheap enough for 10,000 connections.
+type LineServer struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{} // open connections, so Close can hang up on them
+	closed bool
+	wg     sync.WaitGroup // one per goroutine, accept loop included
+}
+
+// ListenLines starts a LineServer on addr, like "127.0.0.1:9000".
+func ListenLines(addr string) (*LineServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &LineServer{listener: listener, conns: map[net.Conn]struct{}{}}
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr is the address the server is listening on, with the real port.
+func (s *LineServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops accepting, hangs up on everyone, and waits for all the goroutines to finish.
+//
+// Closing the listener makes the blocked Accept return an error, that's
+// how you stop an accept loop. Same trick for connections, a goroutine
+// blocked reading from a closed conn gets an error and returns.
+func (s *LineServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *LineServer) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept() // blocks until someone connects
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) { // ErrClosed is us calling Close, anything else is news
+				log.Println("accept:", err)
+			}
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed { // accepted just as Close ran, too late to join
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// handle talks to one client until they QUIT or hang up.
+func (s *LineServer) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	// TCP is a stream of bytes, NOT messages. "PING\nECHO hi\n" could arrive
+	// in one read, or as "PI" then "NG\nECHO hi\n". bufio.Scanner collects
+	// bytes until a newline, so we always get whole lines.
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		command, arg, _ := strings.Cut(lines.Text(), " ") // "ECHO hello there" -> "ECHO", "hello there"
+
+		var reply string
+		switch strings.ToUpper(command) {
+		case "PING":
+			reply = "PONG"
+		case "ECHO":
+			reply = arg
+		case "UPPER":
+			reply = strings.ToUpper(arg)
+		case "QUIT":
+			fmt.Fprintln(conn, "BYE")
+			return
+		default:
+			reply = fmt.Sprintf("ERR unknown command %q", command)
+		}
+
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return // client went away
+		}
+	}
+	// Scan returned false: the client hung up (EOF), or we closed the conn in Close.
+}
@@ -0,0 +1,167 @@
+package main
+
+//	go test go_15_net.go go_15_net_test.go
+//
+// Everything listens on 127.0.0.1:0, a free port picked by the OS, so tests
+// can run side by side without fighting over a port.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// startLineServer starts a LineServer and closes it when the test ends.
+func startLineServer(t *testing.T) *LineServer {
+	t.Helper()
+	server, err := ListenLines("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+// dial connects to addr, with a deadline so a broken server fails the test instead of hanging it.
+func dial(t *testing.T, network, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestLineServerCommands(t *testing.T) {
+	server := startLineServer(t)
+	conn := dial(t, "tcp", server.Addr())
+	replies := bufio.NewScanner(conn)
+
+	tests := []struct{ command, want string }{
+		{"PING", "PONG"},
+		{"ping", "PONG"}, // commands aren't case sensitive
+		{"ECHO hello there", "hello there"},
+		{"ECHO", ""},
+		{"UPPER shout", "SHOUT"},
+		{"DANCE", `ERR unknown command "DANCE"`},
+		{"QUIT", "BYE"},
+	}
+	for _, test := range tests {
+		fmt.Fprintln(conn, test.command)
+		if !replies.Scan() {
+			t.Fatalf("%s: no reply, %v", test.command, replies.Err())
+		}
+		if got := replies.Text(); got != test.want {
+			t.Errorf("%s = %q, want %q", test.command, got, test.want)
+		}
+	}
+	if replies.Scan() {
+		t.Errorf("got %q after QUIT, want the server to hang up", replies.Text())
+	}
+}
+
+func TestLineServerSeveralLinesInOneWrite(t *testing.T) {
+	server := startLineServer(t)
+	conn := dial(t, "tcp", server.Addr())
+
+	// All in one packet, the server still has to see three separate lines.
+	fmt.Fprint(conn, "PING\nECHO one\nECHO two\n")
+	replies := bufio.NewScanner(conn)
+	for _, want := range []string{"PONG", "one", "two"} {
+		if !replies.Scan() || replies.Text() != want {
+			t.Errorf("got %q, %v, want %q", replies.Text(), replies.Err(), want)
+		}
+	}
+}
+
+func TestLineServerClientsAreIndependent(t *testing.T) {
+	server := startLineServer(t)
+	first, second := dial(t, "tcp", server.Addr()), dial(t, "tcp", server.Addr())
+	firstReplies, secondReplies := bufio.NewScanner(first), bufio.NewScanner(second)
+
+	// Talk to the second client while the first sits there, each has its own goroutine.
+	fmt.Fprintln(second, "ECHO second")
+	fmt.Fprintln(first, "ECHO first")
+	if !secondReplies.Scan() || secondReplies.Text() != "second" {
+		t.Errorf("second client got %q", secondReplies.Text())
+	}
+	if !firstReplies.Scan() || firstReplies.Text() != "first" {
+		t.Errorf("first client got %q", firstReplies.Text())
+	}
+}
+
+func TestLineServerClose(t *testing.T) {
+	server, err := ListenLines("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idle := dial(t, "tcp", server.Addr())
+	fmt.Fprintln(idle, "PING")
+	replies := bufio.NewScanner(idle)
+	replies.Scan() // the server has definitely accepted it now
+
+	closed := make(chan error)
+	go func() { closed <- server.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close is stuck waiting on a client that never said QUIT")
+	}
+
+	if replies.Scan() {
+		t.Errorf("idle client got %q, want the server to have hung up", replies.Text())
+	}
+	if conn, err := net.Dial("tcp", server.Addr()); err == nil {
+		conn.Close()
+		t.Error("Dial after Close worked, want nobody listening")
+	}
+}
+
+func TestUDPEcho(t *testing.T) {
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		udpEcho(packetConn)
+	}()
+
+	client := dial(t, "udp", packetConn.LocalAddr().String())
+	reply := make([]byte, 1500)
+	for _, payload := range []string{"hello over udp", "🐹", "x"} {
+		if _, err := client.Write([]byte(payload)); err != nil {
+			t.Fatal(err)
+		}
+		// One Write is one Read on the way back, the datagram arrives whole.
+		// On loopback nothing gets lost, over a real network this could time out.
+		n, err := client.Read(reply)
+		if err != nil || string(reply[:n]) != payload {
+			t.Errorf("echo = %q, %v, want %q", reply[:n], err, payload)
+		}
+	}
+
+	packetConn.Close()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("udpEcho kept running after its conn was closed")
+	}
+
+	// Nobody replies now, the deadline is the only way to find out.
+	client.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	client.Write([]byte("anyone?"))
+	var netErr net.Error
+	if _, err := client.Read(reply); !errors.As(err, &netErr) {
+		t.Errorf("Read with no server = %v, want a timeout or a refused error", err)
+	}
+}
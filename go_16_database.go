@@ -0,0 +1,708 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// database/sql is Go's python DB-API (PEP 249): one API, and a driver per database.
+//
+//	import _ "github.com/lib/pq"        // postgres, the _ import just registers the driver
+//	import _ "modernc.org/sqlite"       // sqlite, pure Go
+//	db, err := sql.Open("sqlite", ":memory:")
+//
+// The standard library ships the API but NO drivers, and this repo sticks to
+// the standard library. So at the bottom of this file is a tiny fake driver,
+// an in-memory users table that understands the few queries below.
+// Swap openFakeDB for sql.Open and everything else stays the same.
+//
+// go_2_funcs.go opens a real postgres connection, this file is what comes after.
+
+// User is the same struct from go_1_intro.go.
+type User struct {
+	Name     string
+	Password string
+}
+
+func main() {
+	store := &fakeStore{}
+	db := openFakeDB(store)
+	defer db.Close() // *sql.DB is a connection POOL, make one at startup and share it
+	ctx := context.Background()
+
+	for _, u := range []User{{Name: "alice", Password: "Gopher123"}, {Name: "bob", Password: "hunter2"}} {
+		if _, err := db.ExecContext(ctx, insertUserSQL, u.Name, u.Password); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	user, err := QueryUserByName(ctx, db, "alice")
+	fmt.Println(user, err) // {alice Gopher123} <nil>
+	_, err = QueryUserByName(ctx, db, "nobody")
+	fmt.Println(errors.Is(err, sql.ErrNoRows)) // true
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Deadlines
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// The caller decides how long it's willing to wait, and the query gives up
+	// when that runs out, instead of holding a connection for a client that left.
+	handler := userHandler(db, 50*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users?name=alice", nil))
+	fmt.Print(recorder.Code, " ", recorder.Body.String()) // 200 alice
+
+	// Now the db is having a bad day, every query takes a second.
+	store.SetDelay(time.Second)
+	start := time.Now()
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users?name=alice", nil))
+	fmt.Print(recorder.Code, " ", recorder.Body.String()) // 504 db took too long
+	fmt.Println(time.Since(start) < time.Second)          // true, gave up at ~50ms, didn't wait out the slow query
+
+	// Same thing without the handler, the error is context.DeadlineExceeded.
+	deadlineCtx, cancel := context.WithDeadline(ctx, time.Now().Add(20*time.Millisecond))
+	_, err = QueryUserByName(deadlineCtx, db, "alice")
+	cancel()
+	fmt.Println(errors.Is(err, context.DeadlineExceeded)) // true
+	store.SetDelay(0)
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Transactions
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	err = insertUsers(ctx, db, []User{{Name: "carol", Password: "pw1"}, {Name: "dave", Password: "pw2"}})
+	fmt.Println(err, countUsers(ctx, db)) // <nil> 4
+
+	// alice is already there, so the second row fails, and erin goes too.
+	err = insertUsers(ctx, db, []User{
+		{Name: "erin", Password: "pw3"},
+		{Name: "alice", Password: "again"},
+		{Name: "frank", Password: "pw4"},
+	})
+	fmt.Println(err)                 // insert alice: UNIQUE constraint failed: users.name
+	fmt.Println(countUsers(ctx, db)) // 4, all or nothing, erin was rolled back
+	_, err = QueryUserByName(ctx, db, "erin")
+	fmt.Println(errors.Is(err, sql.ErrNoRows)) // true
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Scanning many rows
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	all, err := listUsers(ctx, db)
+	fmt.Println(all, err) // [{alice Gopher123} {bob hunter2} {carol pw1} {dave pw2}] <nil>
+
+	// The scan func can be anything returning a T, here just the names.
+	rows, err := db.QueryContext(ctx, selectUsersSQL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	names, err := scanRows(rows, func(rows *sql.Rows) (string, error) {
+		var name, password string
+		err := rows.Scan(&name, &password)
+		return name, err
+	})
+	fmt.Println(names, err) // [alice bob carol dave] <nil>
+
+	// The connection drops after 2 rows. Next just returns false, same as a
+	// normal end, only rows.Err() can tell them apart.
+	store.SetDropAfter(2)
+	all, err = listUsers(ctx, db)
+	fmt.Println(all, err) // [] reading rows: connection reset by peer
+	store.SetDropAfter(0)
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// NULLs
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// nil as an argument writes a NULL, python's None.
+	for _, p := range [][]any{{"alice", "alice@example.com", 30}, {"bob", nil, nil}} {
+		if _, err := db.ExecContext(ctx, insertProfileSQL, p...); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// A Go string can't be "nothing", it's always at least "". So Scan refuses
+	// to turn NULL into one, rather than quietly making it "".
+	var name, plainEmail string
+	var plainAge int
+	err = db.QueryRowContext(ctx, selectProfileSQL, "bob").Scan(&name, &plainEmail, &plainAge)
+	fmt.Println(err) // sql: Scan error on column index 1, name "email": converting NULL to string is unsupported
+
+	alice, err := QueryProfile(ctx, db, "alice")
+	fmt.Println(*alice.Email, alice.Age, err) // alice@example.com 30 <nil>
+
+	bob, err := QueryProfile(ctx, db, "bob")
+	fmt.Println(bob.Email == nil, bob.Age, err) // true 0 <nil>
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Health checks
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	health := StartHealthCheck(db, 10*time.Millisecond)
+	fmt.Println(health.Healthy()) // true, the first ping happens before Start returns
+
+	store.SetDown(true)                                                         // someone tripped over the db's power cable
+	fmt.Println(waitFor(func() bool { return !health.Healthy() }, time.Second)) // true, logs "db is down: ..."
+
+	store.SetDown(false)
+	fmt.Println(waitFor(health.Healthy, time.Second)) // true, logs "db is back up", on a brand new connection
+	health.Stop()
+
+	lazy := StartHealthCheck(db, 0) // no NewTicker panic, 0 means "the default", every 5s
+	fmt.Println(lazy.Healthy())     // true
+	lazy.Stop()
+}
+
+// waitFor checks cond every millisecond until it's true, false if that takes longer than timeout.
+func waitFor(cond func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+const (
+	insertUserSQL    = "INSERT INTO users (name, password) VALUES (?, ?)"
+	selectUserByName = "SELECT name, password FROM users WHERE name = ?"
+	countUsersSQL    = "SELECT COUNT(*) FROM users"
+	selectUsersSQL   = "SELECT name, password FROM users ORDER BY name"
+	insertProfileSQL = "INSERT INTO profiles (name, email, age) VALUES (?, ?, ?)"
+	selectProfileSQL = "SELECT name, email, age FROM profiles WHERE name = ?"
+)
+
+// QueryUserByName looks up one user, sql.ErrNoRows if there isn't one.
+//
+// Always use the ...Context versions (QueryRowContext, ExecContext) and pass
+// ctx along. db.QueryRow without it can't be cancelled, it waits as long as the db does.
+func QueryUserByName(ctx context.Context, db *sql.DB, name string) (User, error) {
+	var user User
+	// ? is a placeholder, the driver sends name separately from the SQL.
+	// NEVER fmt.Sprintf values into a query, that's SQL injection.
+	// (postgres uses $1, $2 instead of ?)
+	err := db.QueryRowContext(ctx, selectUserByName, name).Scan(&user.Name, &user.Password)
+	return user, err
+}
+
+// insertUsers adds all of users, or none of them.
+//
+// Python's DB-API is the other way round: the connection is always in a
+// transaction, and nothing sticks until you conn.commit() (or turn on autocommit).
+// database/sql is autocommit, every db.Exec is its own transaction and sticks
+// right away. For several statements that must succeed or fail together, start
+// one yourself with BeginTx, and run everything through tx, not db.
+//
+//	tx.Commit()    keep everything
+//	tx.Rollback()  undo everything, as if it never ran
+//
+// A tx holds one connection from the pool until it's committed or rolled back,
+// forgetting both leaks it (the outage story in go_2_funcs.go's 7. again).
+func insertUsers(ctx context.Context, db *sql.DB, users []User) error {
+	tx, err := db.BeginTx(ctx, nil) // nil is the default options, sql.TxOptions sets the isolation level
+	if err != nil {
+		return err
+	}
+	// Rollback after a Commit does nothing (returns sql.ErrTxDone), so
+	// deferring it covers every early return below in one line.
+	defer tx.Rollback()
+
+	// Prepare once, run many times, the db only parses the SQL once.
+	// It's prepared on the tx, so it runs inside the transaction.
+	stmt, err := tx.PrepareContext(ctx, insertUserSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, u := range users {
+		if _, err := stmt.ExecContext(ctx, u.Name, u.Password); err != nil {
+			return fmt.Errorf("insert %s: %w", u.Name, err) // the deferred Rollback undoes the rows before this one
+		}
+	}
+	return tx.Commit()
+}
+
+// countUsers is how many rows the users table has, -1 if the query fails.
+func countUsers(ctx context.Context, db *sql.DB) int {
+	var count int
+	if err := db.QueryRowContext(ctx, countUsersSQL).Scan(&count); err != nil {
+		log.Println("count users:", err)
+		return -1
+	}
+	return count
+}
+
+// scanRows reads every row into a T with scan, and closes rows.
+//
+// Every rows loop in Go looks like this, scanRows is so we only write it once:
+//
+//	defer rows.Close()
+//	for rows.Next() { rows.Scan(...) }
+//	rows.Err()
+//
+// The last check is the one everyone forgets. Next returns false at the end of the
+// rows, AND when something broke halfway (connection dropped, bad data), so
+// without rows.Err() half the results look like all of them.
+//
+// scan gets the *sql.Rows, since only the caller knows the columns. Python's
+// cursor.fetchall() hands back tuples, Go makes you say where each column goes.
+func scanRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	defer rows.Close() // hands the connection back to the pool, even if we stop early
+
+	var results []T
+	for rows.Next() {
+		result, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading rows: %w", err) // don't return the partial results, they look complete
+	}
+	return results, nil
+}
+
+// scanUser reads a row from a "name, password" query.
+func scanUser(rows *sql.Rows) (User, error) {
+	var user User
+	err := rows.Scan(&user.Name, &user.Password)
+	return user, err
+}
+
+// listUsers is every user, sorted by name.
+func listUsers(ctx context.Context, db *sql.DB) ([]User, error) {
+	rows, err := db.QueryContext(ctx, selectUsersSQL)
+	if err != nil {
+		return nil, err
+	}
+	return scanRows(rows, scanUser)
+}
+
+// Profile is the optional extras about a user, either can be NULL in the db.
+//
+// Two ways to carry "maybe missing" around in Go:
+//   - a pointer, nil means missing. Like python's Optional[str], you have to check.
+//   - the zero value, 0 means missing. Simpler, as long as 0 can't be a real answer.
+type Profile struct {
+	Name  string
+	Email *string // nil if they never gave one
+	Age   int     // 0 if unknown
+}
+
+// QueryProfile looks up name's profile, sql.ErrNoRows if there isn't one.
+//
+// sql.NullString is a string plus a Valid flag, Valid is false for NULL.
+// There's one per type (NullInt64, NullBool, NullTime...) and the generic
+// sql.Null[T] for anything else. They're awkward to pass around, so we
+// scan into them and convert straight away.
+func QueryProfile(ctx context.Context, db *sql.DB, name string) (Profile, error) {
+	var profile Profile
+	var email sql.NullString
+	var age sql.NullInt64
+	err := db.QueryRowContext(ctx, selectProfileSQL, name).Scan(&profile.Name, &email, &age)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if email.Valid {
+		profile.Email = &email.String
+	}
+	profile.Age = int(age.Int64) // Int64 is 0 when it's NULL, so no Valid check needed for the zero value
+	return profile, nil
+}
+
+// HealthChecker pings the db in the background and remembers if it answered.
+//
+// You DON'T need this to reconnect. *sql.DB is a pool, not one connection:
+// a broken connection gets thrown away and the next query dials a new one,
+// by itself. (python's psycopg2 connection would just stay broken.)
+//
+// It's for reporting. A load balancer or kubernetes asks "are you ready?"
+// (a /ready endpoint returning Healthy), and traffic is moved away from us
+// while the db is down, instead of every request failing.
+type HealthChecker struct {
+	db      *sql.DB
+	every   time.Duration
+	timeout time.Duration // how long one ping may take
+	healthy atomic.Bool   // read by handlers, written by the loop, so atomic
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+const (
+	defaultHealthCheckEvery = 5 * time.Second // used when StartHealthCheck gets 0 or less
+	maxPingTimeout          = time.Second
+)
+
+// StartHealthCheck pings db right away, then every interval until Stop.
+//
+// every <= 0 falls back to defaultHealthCheckEvery, time.NewTicker panics on it,
+// and a 0 timeout would fail every ping before it's sent.
+// Each ping gets every, or maxPingTimeout if that's shorter, so one slow
+// ping is given up on before the next tick, but a check every minute
+// doesn't have to wait a minute to notice the db isn't answering.
+func StartHealthCheck(db *sql.DB, every time.Duration) *HealthChecker {
+	if every <= 0 {
+		every = defaultHealthCheckEvery
+	}
+	h := &HealthChecker{
+		db:      db,
+		every:   every,
+		timeout: min(every, maxPingTimeout),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	h.healthy.Store(h.ping() == nil)
+	go h.loop()
+	return h
+}
+
+// Healthy is whether the last ping worked.
+func (h *HealthChecker) Healthy() bool {
+	return h.healthy.Load()
+}
+
+// Stop ends the loop, and waits for it so nothing logs after we've shut down.
+func (h *HealthChecker) Stop() {
+	close(h.stop)
+	<-h.done
+}
+
+func (h *HealthChecker) loop() {
+	defer close(h.done)
+	ticker := time.NewTicker(h.every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			err := h.ping()
+			wasHealthy := h.healthy.Swap(err == nil)
+			// Only log changes, not every tick, or the logs are all "db ok".
+			switch {
+			case err != nil && wasHealthy:
+				log.Println("db is down:", err)
+			case err == nil && !wasHealthy:
+				log.Println("db is back up")
+			}
+		}
+	}
+}
+
+// ping gives up after h.timeout, a db that takes forever to answer isn't healthy either.
+func (h *HealthChecker) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	return h.db.PingContext(ctx)
+}
+
+// userHandler serves GET /users?name=..., giving the db at most timeout to answer.
+//
+// r.Context() is already cancelled if the client hangs up. WithDeadline adds
+// our own limit on top, whichever comes first wins. (WithTimeout(ctx, d) is
+// shorthand for WithDeadline(ctx, time.Now().Add(d)).)
+func userHandler(db *sql.DB, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(timeout))
+		defer cancel()
+
+		user, err := QueryUserByName(ctx, db, r.URL.Query().Get("name"))
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			http.Error(w, "db took too long", http.StatusGatewayTimeout)
+		case errors.Is(err, sql.ErrNoRows):
+			http.Error(w, "no such user", http.StatusNotFound)
+		case err != nil:
+			log.Println("query user:", err)
+			http.Error(w, "something went wrong", http.StatusInternalServerError)
+		default:
+			fmt.Fprintln(w, user.Name)
+		}
+	}
+}
+
+// ******************************************************************************************************
+// ******************************************************************************************************
+// The fake driver
+// ******************************************************************************************************
+// ******************************************************************************************************
+// A driver is a handful of interfaces from database/sql/driver. database/sql
+// does the pooling, Scan conversions, and context checks, a driver only moves rows.
+//
+//	driver.Connector  hands out connections (sql.OpenDB takes one)
+//	driver.Conn       one connection, Prepare makes a Stmt
+//	driver.Stmt       runs one query, Exec for writes, Query for rows
+//	driver.Rows       the results, one row per Next
+//	driver.Tx         Commit or Rollback
+//
+// Nobody writes these for a living, it's here so the examples above really run.
+
+// fakeStore is the "database server", shared by every connection.
+type fakeStore struct {
+	mu       sync.Mutex
+	users    []User
+	profiles [][]driver.Value // name, email, age, a nil value is NULL
+	delay    time.Duration    // pretend every query takes this long
+
+	dropAfter int // the "connection" breaks after sending this many rows, 0 is never
+
+	down bool // the server is unreachable, pings and new connections fail
+}
+
+// SetDown takes the "server" down, or brings it back up.
+func (s *fakeStore) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *fakeStore) isDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.down
+}
+
+// SetDelay makes every query take d, to play a slow or overloaded db.
+func (s *fakeStore) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// SetDropAfter makes every query fail after sending n rows, 0 turns it off.
+func (s *fakeStore) SetDropAfter(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropAfter = n
+}
+
+// openFakeDB is our stand in for sql.Open.
+func openFakeDB(store *fakeStore) *sql.DB {
+	return sql.OpenDB(fakeConnector{store: store})
+}
+
+type fakeConnector struct {
+	store *fakeStore
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.store.isDown() {
+		return nil, errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")
+	}
+	return &fakeConn{store: c.store}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver { return fakeDriver{} }
+
+// fakeDriver is only needed for sql.Register("fake", ...) style opening, which we don't use.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use openFakeDB")
+}
+
+type fakeConn struct {
+	store *fakeStore
+	tx    *fakeTx // the open transaction, nil if there isn't one
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, store: c.store, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+// Ping makes fakeConn a driver.Pinger, what db.PingContext calls.
+// ErrBadConn tells database/sql this connection is dead, it closes it and
+// tries a fresh one from Connect, that's the built in reconnecting.
+func (c *fakeConn) Ping(context.Context) error {
+	if c.store.isDown() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// Begin starts a transaction on a private copy of the table, Commit swaps it in.
+// A real db locks rows instead of copying, so two transactions can't overwrite
+// each other's changes. Ours is only safe with one writer at a time.
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	c.tx = &fakeTx{conn: c, users: slices.Clone(c.store.users)}
+	return c.tx, nil
+}
+
+// table is the users a query on this connection sees, the transaction's copy if there is one.
+// The store's lock must be held.
+func (c *fakeConn) table() *[]User {
+	if c.tx != nil {
+		return &c.tx.users
+	}
+	return &c.store.users
+}
+
+type fakeTx struct {
+	conn  *fakeConn
+	users []User
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.conn.store.mu.Lock()
+	defer tx.conn.store.mu.Unlock()
+	tx.conn.store.users = tx.users
+	tx.conn.tx = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.conn.tx = nil // just forget the copy
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	store *fakeStore
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 } // -1 is "don't know", database/sql skips checking the arg count
+
+// Exec and Query are the old pre-context versions, every driver still needs them.
+// database/sql calls ExecContext/QueryContext instead when they're there.
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("fake driver: use ExecContext")
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fake driver: use QueryContext")
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.store.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	switch s.query {
+	case insertUserSQL:
+		table := s.conn.table()
+		name := args[0].Value.(string)
+		if slices.ContainsFunc(*table, func(u User) bool { return u.Name == name }) {
+			return nil, errors.New("UNIQUE constraint failed: users.name") // what sqlite says
+		}
+		*table = append(*table, User{Name: name, Password: args[1].Value.(string)})
+		return driver.RowsAffected(1), nil
+	case insertProfileSQL:
+		// database/sql already turned nil into a nil driver.Value, and 30 into int64(30)
+		s.store.profiles = append(s.store.profiles, []driver.Value{args[0].Value, args[1].Value, args[2].Value})
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("fake driver: can't exec %q", s.query)
+}
+
+func (s *fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.store.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	switch s.query {
+	case selectUserByName:
+		rows := &fakeRows{columns: []string{"name", "password"}}
+		for _, u := range *s.conn.table() {
+			if u.Name == args[0].Value.(string) {
+				rows.values = append(rows.values, []driver.Value{u.Name, u.Password})
+			}
+		}
+		return rows, nil
+	case selectUsersSQL:
+		rows := &fakeRows{columns: []string{"name", "password"}, dropAfter: s.store.dropAfter}
+		sorted := slices.SortedFunc(slices.Values(*s.conn.table()), func(a, b User) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, u := range sorted {
+			rows.values = append(rows.values, []driver.Value{u.Name, u.Password})
+		}
+		return rows, nil
+	case selectProfileSQL:
+		rows := &fakeRows{columns: []string{"name", "email", "age"}}
+		for _, profile := range s.store.profiles {
+			if profile[0] == args[0].Value {
+				rows.values = append(rows.values, profile)
+			}
+		}
+		return rows, nil
+	case countUsersSQL:
+		count := int64(len(*s.conn.table())) // drivers hand back int64, Scan converts it to our int
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{count}}}, nil
+	}
+	return nil, fmt.Errorf("fake driver: can't query %q", s.query)
+}
+
+// wait plays the store's delay, giving up early if ctx is done. A real
+// driver does the same while waiting on the network, it's what makes deadlines work.
+func (s *fakeStore) wait(ctx context.Context) error {
+	s.mu.Lock()
+	delay := s.delay
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+
+	dropAfter int // fail after this many rows, 0 is never
+	sent      int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+// Next fills dest with the next row, io.EOF when there are no more.
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.dropAfter > 0 && r.sent == r.dropAfter {
+		return errors.New("connection reset by peer")
+	}
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	r.sent++
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+//	go test go_16_database.go go_16_database_test.go
+//
+// Same fake driver as main, so no real database (or sqlite) needed.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDB is a fresh db with alice and bob in it, closed when the test ends.
+func newTestDB(t *testing.T) (*fakeStore, *sql.DB) {
+	t.Helper() // failures point at the test's line, not this one
+	store := &fakeStore{}
+	db := openFakeDB(store)
+	t.Cleanup(func() { db.Close() })
+
+	for _, u := range []User{{Name: "alice", Password: "Gopher123"}, {Name: "bob", Password: "hunter2"}} {
+		if _, err := db.ExecContext(context.Background(), insertUserSQL, u.Name, u.Password); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, db
+}
+
+func TestQueryUserByNameDeadline(t *testing.T) {
+	store, db := newTestDB(t)
+	store.SetDelay(time.Second) // a deliberately slow db
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := QueryUserByName(ctx, db, "alice")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if took := time.Since(start); took >= time.Second {
+		t.Errorf("took %v, waited out the slow query instead of the deadline", took)
+	}
+}
+
+func TestUserHandler(t *testing.T) {
+	store, db := newTestDB(t)
+	handler := userHandler(db, 20*time.Millisecond)
+
+	tests := []struct {
+		name     string
+		delay    time.Duration
+		wantCode int
+		wantBody string
+	}{
+		{"alice", 0, http.StatusOK, "alice\n"},
+		{"nobody", 0, http.StatusNotFound, "no such user\n"},
+		{"alice", time.Second, http.StatusGatewayTimeout, "db took too long\n"},
+	}
+	for _, test := range tests {
+		store.SetDelay(test.delay)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users?name="+test.name, nil))
+		if recorder.Code != test.wantCode || recorder.Body.String() != test.wantBody {
+			t.Errorf("%s with a %v delay: got %d %q, want %d %q",
+				test.name, test.delay, recorder.Code, recorder.Body.String(), test.wantCode, test.wantBody)
+		}
+	}
+}
+
+func TestInsertUsersAllOrNothing(t *testing.T) {
+	_, db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := insertUsers(ctx, db, []User{{Name: "carol"}, {Name: "dave"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := countUsers(ctx, db); got != 4 {
+		t.Errorf("after a good batch, countUsers = %d, want 4", got)
+	}
+
+	// The middle row is a duplicate, so the rows around it must not stick either.
+	err := insertUsers(ctx, db, []User{{Name: "erin"}, {Name: "alice"}, {Name: "frank"}})
+	if err == nil || !strings.Contains(err.Error(), "insert alice") {
+		t.Errorf("err = %v, want it to name alice", err)
+	}
+	if got := countUsers(ctx, db); got != 4 {
+		t.Errorf("after a failed batch, countUsers = %d, want still 4", got)
+	}
+	for _, name := range []string{"erin", "frank"} {
+		if _, err := QueryUserByName(ctx, db, name); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("%s: err = %v, want sql.ErrNoRows, the insert should be rolled back", name, err)
+		}
+	}
+}
+
+func TestScanRows(t *testing.T) {
+	store, db := newTestDB(t)
+	ctx := context.Background()
+
+	t.Run("all rows", func(t *testing.T) {
+		users, err := listUsers(ctx, db)
+		want := []User{{Name: "alice", Password: "Gopher123"}, {Name: "bob", Password: "hunter2"}}
+		if err != nil || !slices.Equal(users, want) {
+			t.Errorf("listUsers = %v, %v, want %v", users, err, want)
+		}
+	})
+
+	t.Run("connection drops mid iteration", func(t *testing.T) {
+		store.SetDropAfter(1)
+		defer store.SetDropAfter(0)
+		users, err := listUsers(ctx, db)
+		if err == nil || users != nil {
+			t.Errorf("listUsers = %v, %v, want no users and an error, not a partial list", users, err)
+		}
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		rows, err := db.QueryContext(ctx, selectUsersSQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanErr := errors.New("bad row")
+		_, err = scanRows(rows, func(*sql.Rows) (User, error) { return User{}, scanErr })
+		if !errors.Is(err, scanErr) {
+			t.Errorf("err = %v, want it to wrap %v", err, scanErr)
+		}
+	})
+}
+
+func TestQueryProfileNulls(t *testing.T) {
+	_, db := newTestDB(t)
+	ctx := context.Background()
+	for _, p := range [][]any{{"alice", "alice@example.com", 30}, {"bob", nil, nil}} {
+		if _, err := db.ExecContext(ctx, insertProfileSQL, p...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice, err := QueryProfile(ctx, db, "alice")
+	if err != nil || alice.Email == nil || *alice.Email != "alice@example.com" || alice.Age != 30 {
+		t.Errorf("alice = %+v, %v, want alice@example.com and 30", alice, err)
+	}
+
+	bob, err := QueryProfile(ctx, db, "bob")
+	if err != nil || bob.Email != nil || bob.Age != 0 {
+		t.Errorf("bob = %+v, %v, want a nil Email and Age 0 for the NULLs", bob, err)
+	}
+
+	if _, err := QueryProfile(ctx, db, "nobody"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestHealthCheckFollowsPing(t *testing.T) {
+	store, db := newTestDB(t)
+	health := StartHealthCheck(db, time.Millisecond)
+	defer health.Stop()
+
+	if !health.Healthy() {
+		t.Fatal("Healthy() = false right after start, want true")
+	}
+	for _, down := range []bool{true, false, true, false} { // toggle the pings failing
+		store.SetDown(down)
+		if !waitFor(func() bool { return health.Healthy() == !down }, time.Second) {
+			t.Fatalf("with the db down=%v, Healthy() stayed %v", down, health.Healthy())
+		}
+	}
+}
+
+func TestStartHealthCheckDefaultsTheInterval(t *testing.T) {
+	_, db := newTestDB(t)
+	for _, every := range []time.Duration{0, -time.Second} {
+		health := StartHealthCheck(db, every) // would panic in time.NewTicker without the default
+		if health.every != defaultHealthCheckEvery || !health.Healthy() {
+			t.Errorf("StartHealthCheck(db, %v): every = %v, healthy %v, want %v and true",
+				every, health.every, health.Healthy(), defaultHealthCheckEvery)
+		}
+		health.Stop()
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// Counting words in a file, two ways, and what each costs.
+//
+//	go run go_17_wordcount_bench.go
+//
+// Benchmarks run with testing.Benchmark, like go_11_closure_bench.go.
+//
+// wordCountFields is what python's f.read().split() does: read everything, then split.
+// wordCount streams it through a bufio.Scanner a word at a time, like
+// `for line in f: for word in line.split()`.
+//
+// Both are fine for a 1MB file. For a 10GB log the first one needs 10GB+ of RAM,
+// the scanner only ever holds its buffer (4KB to start) plus the counts.
+
+// wordCount counts every whitespace separated word in r.
+// A read error (or a "word" over 64KB) ends the count early, and gets logged.
+// Returning the error too would be the safer API, this keeps the simple
+// python-ish signature, counts = word_count(f).
+func wordCount(r io.Reader) map[string]int {
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords) // split on any whitespace, like strings.Fields
+	for scanner.Scan() {
+		// Text copies the word out of the scanner's buffer into a new string,
+		// lots of tiny allocations, but each one is garbage right away unless it's a new key.
+		counts[scanner.Text()]++
+	}
+	if err := scanner.Err(); err != nil {
+		log.Println("word count stopped early:", err)
+	}
+	return counts
+}
+
+// wordCountFields reads ALL of r into memory, then splits it.
+//
+// Simpler, and strings.Fields doesn't copy, each word is a slice of the big
+// string. But that's also the catch, the whole input has to fit in memory,
+// and any word kept in counts keeps the whole input alive with it.
+// Same signature as wordCount, and the same logging on a read error.
+func wordCountFields(r io.Reader) map[string]int {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		log.Println("word count stopped early:", err) // ReadAll still hands back what it read
+	}
+	counts := map[string]int{}
+	for _, word := range strings.Fields(string(data)) {
+		counts[word]++
+	}
+	return counts
+}
+
+// corpus is ~4MB of made up log lines, the "large file" for the benchmarks.
+var corpus = func() []byte {
+	var buf bytes.Buffer
+	words := []string{"AAPL", "GOOG", "FB", "AMZN", "buy", "sell", "hold", "🍎", "🤓", "📦"}
+	for i := 0; buf.Len() < 4<<20; i++ {
+		fmt.Fprintf(&buf, "%s %s %d\n", words[i%len(words)], words[(i*7)%len(words)], i%1000)
+	}
+	return buf.Bytes()
+}()
+
+func BenchmarkWordCountScanner(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(corpus))) // adds MB/s to the results
+	for i := 0; i < b.N; i++ {
+		wordCount(bytes.NewReader(corpus))
+	}
+}
+
+func BenchmarkWordCountFields(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(corpus)))
+	for i := 0; i < b.N; i++ {
+		wordCountFields(bytes.NewReader(corpus))
+	}
+}
+
+func main() {
+	fmt.Println(wordCount(strings.NewReader("the cat\tin the\n\nhat  "))) // map[cat:1 hat:1 in:1 the:2], tabs, newlines and runs of spaces all split
+
+	// Same answer both ways, on the big input too.
+	fmt.Println(maps.Equal(wordCount(bytes.NewReader(corpus)), wordCountFields(bytes.NewReader(corpus)))) // true
+
+	scanner := testing.Benchmark(BenchmarkWordCountScanner)
+	fields := testing.Benchmark(BenchmarkWordCountFields)
+	fmt.Println("scanner:", scanner, scanner.MemString()) // ~4 MB/op but ~1M allocs/op, a tiny string per word
+	fmt.Println("fields: ", fields, fields.MemString())   // ~100 MB/op in ~100 allocs/op, the file (copied while ReadAll grows) and a slice of every word
+
+	// Speed is about the same. Memory isn't:
+	//   - the scanner allocates a lot of tiny, short lived strings, the total adds up
+	//     (B/op) but only a few KB are alive at any moment.
+	//   - Fields makes a few HUGE allocations, all alive at once, and they grow with the file.
+	// B/op is everything allocated, not the peak. For "will it fit in RAM" the
+	// peak is what counts, and only the scanner's stays flat as the file grows.
+	fmt.Println(scanner.AllocedBytesPerOp() < fields.AllocedBytesPerOp()) // true
+}
@@ -0,0 +1,65 @@
+package main
+
+//	go test go_17_wordcount_bench.go go_17_wordcount_bench_test.go
+//	go test -run xxx -bench . -benchmem go_17_wordcount_bench.go go_17_wordcount_bench_test.go
+//
+// Like go_11, the benchmarks themselves are in go_17_wordcount_bench.go so
+// main can run them, BenchmarkWordCount here is what go test -bench finds.
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func BenchmarkWordCount(b *testing.B) {
+	b.Run("scanner", BenchmarkWordCountScanner)
+	b.Run("fields", BenchmarkWordCountFields)
+}
+
+func TestWordCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]int
+	}{
+		{"spaces tabs and newlines", "the cat\tin the\n\nhat  ", map[string]int{"the": 2, "cat": 1, "in": 1, "hat": 1}},
+		{"case matters", "Go go GO go", map[string]int{"Go": 1, "go": 2, "GO": 1}},
+		{"punctuation sticks to the word", "hi, hi", map[string]int{"hi,": 1, "hi": 1}},
+		{"unicode", "🍎 AAPL 🍎 über", map[string]int{"🍎": 2, "AAPL": 1, "über": 1}},
+		{"empty", "", map[string]int{}},
+		{"only whitespace", " \n\t ", map[string]int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := wordCount(strings.NewReader(test.input)); !maps.Equal(got, test.want) {
+				t.Errorf("wordCount = %v, want %v", got, test.want)
+			}
+			if got := wordCountFields(strings.NewReader(test.input)); !maps.Equal(got, test.want) {
+				t.Errorf("wordCountFields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWordCountWordsSplitAcrossReads(t *testing.T) {
+	// OneByteReader hands the scanner one byte per Read, every word is split
+	// across reads, and 🍎 (4 bytes) across 4 of them.
+	input := "AAPL 🍎 buy AAPL"
+	want := map[string]int{"AAPL": 2, "🍎": 1, "buy": 1}
+	if got := wordCount(iotest.OneByteReader(strings.NewReader(input))); !maps.Equal(got, want) {
+		t.Errorf("wordCount = %v, want %v", got, want)
+	}
+}
+
+func TestWordCountsAgreeOnTheCorpus(t *testing.T) {
+	scanned, fields := wordCount(bytes.NewReader(corpus)), wordCountFields(bytes.NewReader(corpus))
+	if !maps.Equal(scanned, fields) {
+		t.Fatal("wordCount and wordCountFields disagree on the corpus")
+	}
+	if scanned["AAPL"] == 0 || scanned["🍎"] == 0 {
+		t.Errorf("AAPL = %d, 🍎 = %d, want both counted", scanned["AAPL"], scanned["🍎"])
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/iotest"
+	"time"
+)
+
+func main() {
+	// io.Reader and io.Writer are the two most important interfaces in Go.
+	// Files, network connections, http bodies, gzip, hashes, buffers... are all one or both.
+	//
+	//	type Reader interface {
+	//	  Read(p []byte) (n int, err error) // fill p with up to len(p) bytes, io.EOF when done
+	//	}
+	//
+	//	type Writer interface {
+	//	  Write(p []byte) (n int, err error) // write all of p, or return an error
+	//	}
+	//
+	// Like python's "file-like objects", but any type with the method counts
+	// (just like SenderInterface in go_4_structs_interfaces.go).
+	// Because everything speaks the same two methods, you snap them together like legos.
+
+	// io.Copy reads from a Reader and writes to a Writer until EOF,
+	// a small chunk at a time, so it works on 1KB or 100GB the same.
+	message := strings.NewReader("AAPL GOOG FB AMZN\n")
+	copied, err := io.Copy(os.Stdout, message) // AAPL GOOG FB AMZN
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(copied, "bytes copied") // 18 bytes copied
+
+	// io.TeeReader: everything read from it also gets written somewhere else,
+	// like the unix tee command. Here we hash the data while we copy it,
+	// so we only read it once (matters when it's a 4GB upload).
+	hasher := sha256.New() // a hash is an io.Writer too!
+	tee := io.TeeReader(strings.NewReader("hash me while you copy me"), hasher)
+
+	counter := &CountingWriter{}
+	copied, err = io.Copy(counter, tee)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("sha256 %x\n", hasher.Sum(nil))
+	fmt.Println(copied == counter.Count, counter.Count) // true 25
+
+	// io.MultiWriter: one Write goes to all of them, here a file AND stdout.
+	file, err := os.CreateTemp("", "go_18_io_*.log")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(file.Name()) // cleanup the temp file, deferred runs last-in first-out
+	defer file.Close()           // ALWAYS close files
+
+	logCounter := &CountingWriter{}
+	both := io.MultiWriter(file, os.Stdout, logCounter)
+	fmt.Fprintln(both, "written to the file and the screen") // works with anything that's a Writer
+
+	fileInfo, _ := file.Stat()
+	fmt.Println(fileInfo.Size() == logCounter.Count) // true
+
+	// A Read can block forever: a network peer that never answers, a pipe nobody
+	// writes to. io.ReadAll would wait with it, readAllWithTimeout gives up.
+	data, err := readAllWithTimeout(context.Background(), strings.NewReader("quick"))
+	fmt.Println(string(data), err) // quick <nil>
+
+	stuckReader, stuckWriter := io.Pipe() // reads block until someone writes, and nobody will
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = readAllWithTimeout(ctx, stuckReader)
+	fmt.Println(err, time.Since(start) < time.Second) // context deadline exceeded true
+
+	// The reading goroutine is still stuck in Read. We own the pipe, so we
+	// close it, that's what unsticks the goroutine. The other end can tell.
+	stuckReader.Close()
+	_, err = stuckWriter.Write([]byte("too late"))
+	fmt.Println(err) // io: read/write on closed pipe
+
+	// bufio.Scanner splits a Reader into tokens, lines by default (go_15_net.go uses it).
+	// Split swaps in another rule, bufio.ScanWords, bufio.ScanRunes, or our own.
+	// The intro's comma separated string, but streamed instead of strings.Split:
+	fields, err := splitCommas(strings.NewReader("one,two,three,four "))
+	fmt.Printf("%q %v\n", fields, err) // ["one" "two" "three" "four "] <nil>
+
+	// OneByteReader hands over 1 byte per Read, so every token arrives
+	// in pieces. scanCommas keeps asking for more until it sees a comma.
+	fields, err = splitCommas(iotest.OneByteReader(strings.NewReader("one,two,,three")))
+	fmt.Printf("%q %v\n", fields, err) // ["one" "two" "" "three"] <nil>, same, and the empty field kept
+
+	// A token bigger than the scanner's max buffer is an error, not a cut off token.
+	// (The default max is 64KB, a huge line trips it, scanner.Buffer raises it.)
+	tiny := bufio.NewScanner(strings.NewReader("one,three,two"))
+	tiny.Buffer(make([]byte, 0, 4), 4) // at most 4 bytes, "one," fits, "three," doesn't
+	tiny.Split(scanCommas)
+	for tiny.Scan() {
+		fmt.Println(tiny.Text()) // one
+	}
+	fmt.Println(tiny.Err()) // bufio.Scanner: token too long
+
+	// Walking a directory tree, python's os.walk. Make a small project to walk:
+	//
+	//	main.go
+	//	pkg/users.go  pkg/users.txt  pkg/deep/er/sort.go
+	//	vendor/lib/lib.go   .git/hooks/hook.go   <-- skipped
+	root, err := os.MkdirTemp("", "go_18_walk_*")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	for _, name := range []string{
+		"main.go", "pkg/users.go", "pkg/users.txt", "pkg/deep/er/sort.go",
+		"vendor/lib/lib.go", ".git/hooks/hook.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name)) // FromSlash turns / into \ on windows
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	goFiles, err := findGoFiles(root)
+	for _, path := range goFiles {
+		relative, _ := filepath.Rel(root, path)
+		fmt.Println(filepath.ToSlash(relative))
+	}
+	fmt.Println(err)
+	// main.go
+	// pkg/deep/er/sort.go
+	// pkg/users.go
+	// <nil>
+}
+
+// findGoFiles returns the path of every .go file under root, in lexical
+// order, skipping vendor and hidden (.git, .idea...) directories.
+//
+// WalkDir calls our func for every file and directory, parents before
+// children. What the func returns decides what happens next:
+//
+//	nil          keep going
+//	fs.SkipDir   on a directory: don't go inside it, carry on with the rest
+//	             (on a file it skips the rest of that file's directory)
+//	fs.SkipAll   stop walking, and WalkDir returns nil
+//	any error    stop walking, and WalkDir returns that error
+//
+// WalkDir is the newer, faster filepath.Walk. It hands over a DirEntry
+// (name and type, straight from reading the directory) instead of
+// calling os.Stat on every single file.
+func findGoFiles(root string) ([]string, error) {
+	var goFiles []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err // couldn't read this directory (permissions?), give up, or return nil to skip it
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (name == "vendor" || strings.HasPrefix(name, ".")) { // root itself might be "."
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" {
+			goFiles = append(goFiles, path)
+		}
+		return nil
+	})
+	return goFiles, err
+}
+
+// scanCommas is a bufio.SplitFunc that splits on commas instead of newlines.
+//
+// The Scanner calls it with what it has buffered so far. It answers:
+//
+//	advance  how many bytes of data to use up (the token, plus the comma)
+//	token    the token found, nil for "none yet"
+//	err      stop scanning with this error
+//
+// 0, nil, nil means "not enough yet": the Scanner reads more, and calls again
+// with a longer data. atEOF is true once the Reader is finished, then whatever
+// is left is the last token (like a last line with no \n after it).
+func scanCommas(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, ','); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// splitCommas reads every comma separated field from r.
+func splitCommas(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanCommas) // before the first Scan
+	var fields []string
+	for scanner.Scan() {
+		fields = append(fields, scanner.Text()) // Text copies the token, Bytes would be reused by the next Scan
+	}
+	return fields, scanner.Err()
+}
+
+// readAllWithTimeout is io.ReadAll, but it stops waiting once ctx is done.
+//
+// There's no way to interrupt a Read that's in progress, io.Reader has no
+// ctx parameter. So the reading happens in its own goroutine, and we select
+// on its result OR ctx.Done(), whichever comes first.
+//
+// The catch: giving up doesn't stop that goroutine, it's still stuck in Read.
+// Do that 10,000 times and it's 10,000 leaked goroutines (and whatever they hold).
+// It never closes r, r belongs to the caller (it might be os.Stdin, or a
+// conn that's still in use). After a timeout, the caller unsticks it:
+//   - close r, if you own it. A blocked Read on a closed file/conn/pipe
+//     returns an error right away, so the goroutine finishes.
+//   - for a net.Conn, conn.SetReadDeadline does the same thing without closing it.
+//   - the result channel has a buffer of 1, so a late goroutine can still send
+//     and exit, instead of blocking forever on a send nobody receives.
+func readAllWithTimeout(ctx context.Context, r io.Reader) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		data, err := io.ReadAll(r)
+		results <- result{data, err}
+	}()
+
+	select {
+	case res := <-results:
+		return res.data, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CountingWriter is our own io.Writer, it throws the data away and just counts bytes.
+// Wire it into io.Copy / io.MultiWriter to see how much went by.
+type CountingWriter struct {
+	Count int64
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	c.Count += int64(len(p))
+	return len(p), nil // a Writer must say it wrote everything, or return an error
+}
@@ -0,0 +1,165 @@
+package main
+
+// Tests live next to the file they test, in the same package, so they can
+// call unexported funcs like scanCommas. No go.mod needed, name both files:
+//
+//	go test go_18_io.go go_18_io_test.go
+//
+// python's pytest finds test_*.py, go test finds *_test.go and runs every
+// func TestXxx(t *testing.T) in it.
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestCountingWriterMatchesCopiedLength(t *testing.T) {
+	input := strings.Repeat("AAPL GOOG FB AMZN 📈\n", 1000) // multi-byte emoji, bytes not runes
+	counter := &CountingWriter{}
+
+	copied, err := io.Copy(counter, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != counter.Count || counter.Count != int64(len(input)) {
+		t.Errorf("copied %d, counted %d, want both %d", copied, counter.Count, len(input))
+	}
+}
+
+func TestReadAllWithTimeout(t *testing.T) {
+	t.Run("reader finishes first", func(t *testing.T) {
+		data, err := readAllWithTimeout(context.Background(), strings.NewReader("quick"))
+		if string(data) != "quick" || err != nil {
+			t.Errorf("got %q, %v, want \"quick\", nil", data, err)
+		}
+	})
+
+	t.Run("deadline wins over a blocked reader", func(t *testing.T) {
+		stuck, _ := io.Pipe() // nobody ever writes, every Read blocks
+		defer stuck.Close()   // unsticks the reading goroutine once we're done
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := readAllWithTimeout(ctx, stuck)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want context.DeadlineExceeded", err)
+		}
+		if took := time.Since(start); took > time.Second {
+			t.Errorf("took %v, the deadline should have stopped the wait after ~20ms", took)
+		}
+	})
+
+	t.Run("read errors come through", func(t *testing.T) {
+		broken := iotest.ErrReader(errors.New("disk on fire"))
+		if _, err := readAllWithTimeout(context.Background(), broken); err == nil || err.Error() != "disk on fire" {
+			t.Errorf("err = %v, want disk on fire", err)
+		}
+	})
+}
+
+func TestSplitCommas(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"one field", "one", []string{"one"}},
+		{"empty fields kept", "one,,two", []string{"one", "", "two"}},
+		{"trailing comma", "one,two,", []string{"one", "two"}}, // like a last line ending in \n
+		{"spaces kept", " a , b", []string{" a ", " b"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := splitCommas(strings.NewReader(test.input))
+			if err != nil || !slices.Equal(got, test.want) {
+				t.Errorf("splitCommas(%q) = %q, %v, want %q", test.input, got, err, test.want)
+			}
+		})
+	}
+}
+
+// Tokens split over several Reads have to come out whole.
+func TestScanCommasAcrossBufferBoundaries(t *testing.T) {
+	input := "alpha,beta,gamma," + strings.Repeat("x", 100) + ",delta"
+	want := []string{"alpha", "beta", "gamma", strings.Repeat("x", 100), "delta"}
+
+	readers := map[string]func(io.Reader) io.Reader{
+		"one byte per Read": iotest.OneByteReader,
+		"half per Read":     iotest.HalfReader,
+		"data with EOF":     iotest.DataErrReader,
+	}
+	for name, wrap := range readers {
+		t.Run(name, func(t *testing.T) {
+			got, err := splitCommas(wrap(strings.NewReader(input)))
+			if err != nil || !slices.Equal(got, want) {
+				t.Errorf("got %q, %v, want %q", got, err, want)
+			}
+		})
+	}
+
+	// A buffer smaller than the input makes the Scanner slide and grow it
+	// between calls, each token still arrives whole.
+	t.Run("small scanner buffer", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader(input))
+		scanner.Buffer(make([]byte, 0, 2), 1024)
+		scanner.Split(scanCommas)
+		var got []string
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil || !slices.Equal(got, want) {
+			t.Errorf("got %q, %v, want %q", got, err, want)
+		}
+	})
+}
+
+func TestFindGoFiles(t *testing.T) {
+	root := t.TempDir() // removed by the test framework afterwards
+	for _, name := range []string{
+		"main.go", "README.md", "pkg/users.go", "pkg/users_test.go", "pkg/deep/er/sort.go",
+		"vendor/lib/lib.go", ".git/hooks/hook.go", "pkg/.cache/gen.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	goFiles, err := findGoFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, path := range goFiles {
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(relative))
+	}
+	want := []string{"main.go", "pkg/deep/er/sort.go", "pkg/users.go", "pkg/users_test.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("findGoFiles = %q, want %q", got, want)
+	}
+}
+
+func TestFindGoFilesMissingRoot(t *testing.T) {
+	_, err := findGoFiles(filepath.Join(t.TempDir(), "nope"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want os.ErrNotExist", err)
+	}
+}
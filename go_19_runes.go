@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+func main() {
+	// The intro said letters[0] is a "rune, not a char". What's going on?
+	//
+	// A Go string is a read-only slice of BYTES, encoded as utf8.
+	// In utf8, a-z take 1 byte each, é takes 2, most emoji take 4.
+	//
+	// A rune is one unicode "code point" (an int32), what you'd think of
+	// as one character. Python 3 str counts code points too, Go just
+	// makes you pick: bytes or runes.
+	cafe := "café"
+
+	fmt.Println(len(cafe))                    // 5 ❗ len counts BYTES, c a f are 1 each, é is 2
+	fmt.Println(utf8.RuneCountInString(cafe)) // 4, python len("café") is also 4
+
+	// Indexing a string gives a BYTE, not a character.
+	fmt.Println(cafe[3])         // 195, just the first byte of é
+	fmt.Println(string(cafe[3])) // Ã ❌ half of é, garbled
+	fmt.Println(cafe[0:4])       // caf� ❌ cut in the middle of é
+
+	// Convert to []rune for safe indexing by character.
+	// (This copies the whole string, 4 bytes per rune, so don't do it in a hot loop.)
+	cafeRunes := []rune(cafe)
+	fmt.Println(len(cafeRunes))                       // 4
+	fmt.Println(string(cafeRunes[3]))                 // é ✅
+	fmt.Println(string(cafeRunes[0:3]))               // caf ✅
+	fmt.Printf("%c %U\n", cafeRunes[3], cafeRunes[3]) // é U+00E9
+
+	// range over a string decodes runes for you, and i is the BYTE offset.
+	// There is no byte 4 printed, because é took up bytes 3 and 4.
+	for i, r := range cafe {
+		fmt.Printf("byte %d: %c\n", i, r) // byte 0: c, byte 1: a, byte 2: f, byte 3: é
+	}
+
+	// Emoji are 4 bytes each, same story in the stock ticker from go_3_goroutines.go.
+	stocks := "🍎🤓🤢📦"
+	fmt.Println(len(stocks), utf8.RuneCountInString(stocks)) // 16 4
+	for i, r := range stocks {
+		fmt.Printf("byte %d: %c\n", i, r) // byte 0: 🍎, byte 4: 🤓, byte 8: 🤢, byte 12: 📦
+	}
+
+	// Bad utf8 (e.g. we cut a string in the middle of a rune) is detectable.
+	fmt.Println(utf8.ValidString(cafe), utf8.ValidString(cafe[0:4])) // true false
+
+	// Reversing a string, the python s[::-1].
+	fmt.Println(reverse(stocks))                                 // 📦🤢🤓🍎 ✅
+	fmt.Println(reverse(reverse(stocks)) == stocks)              // true, round trips
+	fmt.Println(utf8.ValidString(reverseBytes(stocks)))          // false ❌ each emoji's 4 bytes got flipped, garbage
+	fmt.Println(reverse("café"), reverseBytes("café") == "éfac") // éfac false
+
+	// Limitation: some "characters" are several runes glued together (a "grapheme cluster").
+	// 👍🏽 is 👍 + a skin tone rune, and reversing moves the skin tone to the front.
+	// For those you need a grapheme library (e.g. github.com/rivo/uniseg).
+	thumbsUp := "👍🏽"
+	fmt.Println(utf8.RuneCountInString(thumbsUp), reverse(thumbsUp) == thumbsUp) // 2 false
+}
+
+// reverse flips s by runes, so multi-byte characters stay in one piece.
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i] // swap, no temp variable needed
+	}
+	return string(runes)
+}
+
+// reverseBytes flips s byte by byte ❌ DON'T DO THIS, it's here to show what breaks.
+// Fine for plain ascii, corrupts anything multi-byte (é, emoji, chinese...).
+func reverseBytes(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
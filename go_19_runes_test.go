@@ -0,0 +1,107 @@
+package main
+
+//	go test go_19_runes.go go_19_runes_test.go
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRuneCounts(t *testing.T) {
+	tests := []struct {
+		s            string
+		bytes, runes int
+	}{
+		{"café", 5, 4}, // é is 2 bytes
+		{"hello", 5, 5},
+		{"🍎🤓🤢📦", 16, 4}, // the stock emoji, 4 bytes each
+		{"日本語", 9, 3},
+		{"👍🏽", 8, 2}, // one thumb on screen, two runes, see TestReverseGraphemeClusters
+		{"", 0, 0},
+	}
+	for _, test := range tests {
+		if got := len(test.s); got != test.bytes {
+			t.Errorf("len(%q) = %d, want %d", test.s, got, test.bytes)
+		}
+		if got := utf8.RuneCountInString(test.s); got != test.runes {
+			t.Errorf("RuneCountInString(%q) = %d, want %d", test.s, got, test.runes)
+		}
+		if got := len([]rune(test.s)); got != test.runes {
+			t.Errorf("len([]rune(%q)) = %d, want %d", test.s, got, test.runes)
+		}
+	}
+}
+
+func TestRangeGivesByteOffsets(t *testing.T) {
+	var offsets []int
+	var runes []rune
+	for i, r := range "a🍎é" {
+		offsets = append(offsets, i)
+		runes = append(runes, r)
+	}
+	// a is byte 0, 🍎 is bytes 1-4, é is bytes 5-6.
+	if len(offsets) != 3 || offsets[0] != 0 || offsets[1] != 1 || offsets[2] != 5 {
+		t.Errorf("offsets = %v, want [0 1 5]", offsets)
+	}
+	if string(runes) != "a🍎é" {
+		t.Errorf("runes = %q, want each decoded whole", string(runes))
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct{ s, want string }{
+		{"hello", "olleh"},
+		{"café", "éfac"},
+		{"🍎🤓🤢📦", "📦🤢🤓🍎"},
+		{"AAPL 🍎", "🍎 LPAA"},
+		{"a", "a"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		got := reverse(test.s)
+		if got != test.want {
+			t.Errorf("reverse(%q) = %q, want %q", test.s, got, test.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("reverse(%q) = %q, isn't valid utf8", test.s, got)
+		}
+		if back := reverse(got); back != test.s {
+			t.Errorf("reverse(reverse(%q)) = %q, want it back", test.s, back)
+		}
+	}
+}
+
+func TestReverseBytesCorruptsMultiByte(t *testing.T) {
+	if got := reverseBytes("hello"); got != "olleh" {
+		t.Errorf("reverseBytes(hello) = %q, plain ascii should be fine", got)
+	}
+	for _, s := range []string{"café", "🍎🤓🤢📦", "日本語"} {
+		if got := reverseBytes(s); utf8.ValidString(got) || got == reverse(s) {
+			t.Errorf("reverseBytes(%q) = %q, want broken utf8", s, got)
+		}
+		// Flipping the bytes twice does undo it, the damage is in the middle step.
+		if back := reverseBytes(reverseBytes(s)); back != s {
+			t.Errorf("reverseBytes twice = %q, want %q", back, s)
+		}
+	}
+}
+
+func TestReverseGraphemeClusters(t *testing.T) {
+	// The known limitation: 👍🏽 is 👍 followed by a skin tone modifier rune.
+	// reverse keeps each rune whole, but puts the modifier first, so it shows
+	// up as 🏽👍. Still valid utf8, still round trips, just not what a person expects.
+	thumbsUp := "👍🏽"
+	got := reverse(thumbsUp)
+	if got != "🏽👍" {
+		t.Errorf("reverse(%q) = %q, want the runes swapped", thumbsUp, got)
+	}
+	if !utf8.ValidString(got) || reverse(got) != thumbsUp {
+		t.Errorf("reverse(%q) should still be valid and round trip", thumbsUp)
+	}
+
+	// Same for e + a combining accent, which looks exactly like é.
+	combining := "e\u0301"
+	if got := reverse(combining); got != "\u0301e" {
+		t.Errorf("reverse(e + accent) = %q, want the accent first", got)
+	}
+}
@@ -11,6 +11,7 @@ package main
 // These imports are all from Golang (the standard library),
 // no need to install additional libraries.
 import (
+	"cmp"
 	"fmt"
 	"log"
 	"sort"
@@ -372,6 +373,10 @@ func main() {
 	// Write to the map, Update the map
 	nameToAge["Bob"] = 34
 
+	// Print the map in the same order every time (see printSorted below).
+	nameToAge["Cindy"] = 27
+	printSorted(nameToAge) // key[Alice] value[33], key[Bob] value[34], key[Cindy] value[27]
+
 	// Delete from the map
 	delete(nameToAge, "Bob") // Remove key val, ignores if none there
 
@@ -386,7 +391,7 @@ func main() {
 	// Read from the map, checking if we have it
 	dorisBuddyUser, keyExists := buddyMap[dorisUser]
 	if keyExists {
-		fmt.Println(dorisBuddyUser) // {Name: "Evan", Password: "Gopher456"}
+		fmt.Println(dorisBuddyUser) // {Name: "Evan", Password: "Gopher456"}, oops, the password too! See User.String in go_4_structs_interfaces.go
 	}
 
 	// Iterate through the map
@@ -443,3 +448,30 @@ func main() {
 	_, _, _ = i, j, k
 	_, _, _, _, _, _, _, _, _ = emptySlice, myEmptySlice, numFromArr, numFromSlice, partOfArr, partOfSlice, everyThingBefore4, everyThingStartingAt2, nameYearSlice
 }
+
+// Ranging over a map is random on purpose, Go shuffles it so nobody relies on the order.
+// That bites when printing results, or comparing output to what you expected:
+// "key[Bob] key[Alice]" one run, "key[Alice] key[Bob]" the next.
+//
+// So any time output needs to be reproducible (logs you diff, tests that compare
+// strings, files you commit), grab the keys, sort them, and range over those instead.
+//
+// cmp.Ordered means any key type that works with < (ints, floats, strings).
+// V any means the values can be anything, we don't sort by them.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m)) // we know the size up front, allocate once
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j] // Ascending
+	})
+	return keys
+}
+
+// printSorted prints a map in key order, same output every run.
+func printSorted[K cmp.Ordered, V any](m map[K]V) {
+	for _, k := range sortedKeys(m) {
+		fmt.Printf("key[%v] value[%v]\n", k, m[k])
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+//	go test go_1_intro.go go_1_intro_test.go
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSortedKeysIsStable(t *testing.T) {
+	ages := map[string]int{"Doris": 41, "Alice": 30, "Evan": 25, "Bob": 52, "Carol": 38}
+	want := []string{"Alice", "Bob", "Carol", "Doris", "Evan"}
+
+	// Ranging over ages directly comes out in a different order from run to run,
+	// so a test comparing that to want would pass sometimes. sortedKeys never changes.
+	for run := 0; run < 20; run++ {
+		if got := sortedKeys(ages); !slices.Equal(got, want) {
+			t.Fatalf("run %d: sortedKeys = %q, want %q", run, got, want)
+		}
+	}
+
+	if got := sortedKeys(map[int]bool{3: true, -1: false, 2: true}); !slices.Equal(got, []int{-1, 2, 3}) {
+		t.Errorf("int keys = %v, want [-1 2 3]", got)
+	}
+	if got := sortedKeys(map[string]int{}); len(got) != 0 {
+		t.Errorf("empty map = %v, want no keys", got)
+	}
+}
+
+func TestPrintSorted(t *testing.T) {
+	// printSorted writes straight to stdout, so swap os.Stdout for a pipe while it runs.
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	printSorted(map[string]int{"b": 2, "c": 3, "a": 1})
+	os.Stdout = stdout
+	writer.Close()
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{"key[a] value[1]", "key[b] value[2]", "key[c] value[3]", ""}, "\n")
+	if string(out) != want {
+		t.Errorf("printed %q, want %q", out, want)
+	}
+}
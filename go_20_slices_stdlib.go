@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// The slices package
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Go 1.21 added the slices package, helpers you'd otherwise write
+	// as a for loop every single time. Older Go code (and the intro) predates it.
+	numbersSlice := []int{2, 3, 5, 7, 11, 13}
+
+	// Contains, python: 7 in numbers
+	//
+	// The hand-rolled version you'll still see all over older code:
+	//
+	//	found := false
+	//	for _, n := range numbersSlice {
+	//	  if n == 7 {
+	//	    found = true
+	//	    break
+	//	  }
+	//	}
+	fmt.Println(slices.Contains(numbersSlice, 7)) // true
+
+	// Index, python: numbers.index(7), but -1 instead of raising ValueError
+	fmt.Println(slices.Index(numbersSlice, 7))  // 3
+	fmt.Println(slices.Index(numbersSlice, 42)) // -1
+
+	// Equal, python: a == b
+	// Slices can't use == in Go (compile error), only == nil.
+	fmt.Println(slices.Equal(numbersSlice, []int{2, 3, 5, 7, 11, 13})) // true
+	fmt.Println(slices.Equal(numbersSlice, []int{2, 3, 5}))            // false
+
+	// Clone, python: numbers.copy()
+	// A plain "b := a" shares the same underlying array, edits show up in both!
+	shared := numbersSlice
+	cloned := slices.Clone(numbersSlice)
+	shared[0] = 1
+	fmt.Println(numbersSlice[0], cloned[0]) // 1 2, shared edited the original, clone didn't
+	numbersSlice[0] = 2                     // put it back
+
+	// Insert, python: numbers.insert(2, 4)
+	// Like append, it can reallocate, so ALWAYS use the returned slice.
+	numbersSlice = slices.Insert(numbersSlice, 2, 4)
+	fmt.Println(numbersSlice) // [2 3 4 5 7 11 13]
+
+	// Delete, python: del numbers[2:3]  (start inclusive, end exclusive, like [1:4] slicing)
+	numbersSlice = slices.Delete(numbersSlice, 2, 3)
+	fmt.Println(numbersSlice) // [2 3 5 7 11 13]
+
+	// Deleting the last element
+	numbersSlice = slices.Delete(numbersSlice, len(numbersSlice)-1, len(numbersSlice))
+	fmt.Println(numbersSlice) // [2 3 5 7 11]
+
+	// These are all fine with empty (and nil) slices, no special cases needed.
+	var emptySlice []int
+	fmt.Println(slices.Contains(emptySlice, 7))    // false
+	fmt.Println(slices.Index(emptySlice, 7))       // -1
+	fmt.Println(slices.Equal(emptySlice, []int{})) // true, nil and empty count as equal
+	fmt.Println(slices.Insert(emptySlice, 0, 7))   // [7]
+	fmt.Println(len(slices.Clone(emptySlice)))     // 0
+	// slices.Delete(emptySlice, 0, 1)             <-- panics though, out of range, same as emptySlice[0:1]
+
+	// A few more worth knowing:
+	// slices.Sort(s), slices.Max(s), slices.Min(s), slices.Reverse(s), slices.IndexFunc(s, func)
+	fmt.Println(slices.Max(numbersSlice)) // 11, (Max and Min panic on an empty slice, check len first)
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// The maps package
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Go 1.21 added maps too, the same idea for maps.
+	nameToAge := map[string]int{
+		"Bob":   42,
+		"Alice": 33,
+	}
+
+	// maps.Keys / maps.Values, python: d.keys() / d.values()
+	//
+	// In Go 1.23+ these return an "iterator" (iter.Seq) instead of a slice.
+	// Like python's dict_keys view, nothing is copied until you loop over it.
+	for name := range maps.Keys(nameToAge) {
+		fmt.Println(name) // Bob, Alice in random order, it's still a map
+	}
+
+	// Want a sorted slice? slices.Sorted collects an iterator and sorts it,
+	// python: sorted(d.keys())
+	names := slices.Sorted(maps.Keys(nameToAge))
+	fmt.Println(names) // [Alice Bob]
+
+	ages := slices.Sorted(maps.Values(nameToAge))
+	fmt.Println(ages) // [33 42]
+
+	// slices.Collect gives an unsorted slice, python: list(d.values())
+	fmt.Println(len(slices.Collect(maps.Values(nameToAge)))) // 2
+
+	// Clone, python: d.copy()
+	// Like slices, "b := a" shares the same map, edits show up in both!
+	sharedAges := nameToAge
+	clonedAges := maps.Clone(nameToAge)
+	sharedAges["Bob"] = 99
+	fmt.Println(nameToAge["Bob"], clonedAges["Bob"]) // 99 42, shared edited the original, clone didn't
+
+	clonedAges["Cindy"] = 27
+	_, cindyInOriginal := nameToAge["Cindy"]
+	fmt.Println(cindyInOriginal) // false, adding to the clone doesn't touch the original
+
+	// Equal, python: a == b
+	// Maps can't use == in Go either, only == nil.
+	// Equal means same keys with the same values, order never matters.
+	fmt.Println(maps.Equal(nameToAge, map[string]int{"Alice": 33, "Bob": 99})) // true
+	fmt.Println(maps.Equal(nameToAge, clonedAges))                             // false
+	var nilAges map[string]int
+	fmt.Println(maps.Equal(map[string]int{}, nilAges)) // true, empty and nil count as equal
+}
@@ -0,0 +1,159 @@
+package main
+
+//	go test go_20_slices_stdlib.go go_20_slices_stdlib_test.go
+//
+// go_20 is a tour of the stdlib, so these test the slices and maps
+// packages themselves, the behavior the tour promises.
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestSlicesOnEmptySlices(t *testing.T) {
+	for name, empty := range map[string][]int{"nil": nil, "empty": {}} {
+		if slices.Contains(empty, 7) {
+			t.Errorf("%s: Contains = true", name)
+		}
+		if got := slices.Index(empty, 7); got != -1 {
+			t.Errorf("%s: Index = %d, want -1", name, got)
+		}
+		if !slices.Equal(empty, nil) || !slices.Equal(empty, []int{}) {
+			t.Errorf("%s: want Equal to both nil and []int{}", name)
+		}
+		if got := slices.Insert(empty, 0, 7); !slices.Equal(got, []int{7}) {
+			t.Errorf("%s: Insert = %v, want [7]", name, got)
+		}
+		if got := slices.Clone(empty); len(got) != 0 {
+			t.Errorf("%s: Clone = %v, want empty", name, got)
+		}
+		if got := slices.Delete(empty, 0, 0); len(got) != 0 { // deleting nothing is fine, [0:0] is in range
+			t.Errorf("%s: Delete(0, 0) = %v, want empty", name, got)
+		}
+	}
+}
+
+func TestSlicesDeleteOutOfRangePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Delete(empty, 0, 1) didn't panic")
+		}
+	}()
+	_ = slices.Delete([]int{}, 0, 1)
+}
+
+func TestSlicesDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end int
+		want       []int
+	}{
+		{"the last element", 4, 5, []int{2, 3, 5, 7}},
+		{"the first element", 0, 1, []int{3, 5, 7, 11}},
+		{"the middle", 1, 3, []int{2, 7, 11}},
+		{"nothing", 2, 2, []int{2, 3, 5, 7, 11}},
+		{"everything", 0, 5, []int{}},
+	}
+	for _, test := range tests {
+		numbers := []int{2, 3, 5, 7, 11}
+		got := slices.Delete(numbers, test.start, test.end)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: Delete(%d, %d) = %v, want %v", test.name, test.start, test.end, got, test.want)
+		}
+		// Delete works in place, the returned slice shares numbers' array.
+		// Since Go 1.22 the leftover tail is zeroed, so stale values can't leak.
+		for _, leftover := range numbers[len(got):] {
+			if leftover != 0 {
+				t.Errorf("%s: leftover tail %v, want zeroed", test.name, numbers[len(got):])
+				break
+			}
+		}
+	}
+}
+
+func TestSlicesInsertAndIndex(t *testing.T) {
+	numbers := slices.Insert([]int{2, 3, 5}, 3, 7, 11) // at len is fine, it appends
+	if !slices.Equal(numbers, []int{2, 3, 5, 7, 11}) {
+		t.Errorf("Insert at the end = %v", numbers)
+	}
+	if got := slices.Index(numbers, 7); got != 3 {
+		t.Errorf("Index(7) = %d, want 3", got)
+	}
+	if got := slices.Index([]int{1, 2, 1}, 1); got != 0 {
+		t.Errorf("Index with a duplicate = %d, want the first one", got)
+	}
+}
+
+func TestSlicesCloneIsIndependent(t *testing.T) {
+	numbers := []int{2, 3, 5}
+	shared, cloned := numbers, slices.Clone(numbers)
+	shared[0] = 1
+	if numbers[0] != 1 || cloned[0] != 2 {
+		t.Errorf("numbers[0] = %d, cloned[0] = %d, want 1 shared and 2 cloned", numbers[0], cloned[0])
+	}
+}
+
+func TestMapsCloneIsIndependent(t *testing.T) {
+	nameToAge := map[string]int{"Bob": 42, "Alice": 33}
+	cloned := maps.Clone(nameToAge)
+
+	cloned["Bob"] = 99
+	cloned["Cindy"] = 27
+	delete(cloned, "Alice")
+	if want := map[string]int{"Bob": 42, "Alice": 33}; !maps.Equal(nameToAge, want) {
+		t.Errorf("original = %v after editing the clone, want %v", nameToAge, want)
+	}
+
+	shared := nameToAge
+	shared["Bob"] = 50
+	if nameToAge["Bob"] != 50 {
+		t.Errorf("original Bob = %d, want 50, a plain assignment shares the map", nameToAge["Bob"])
+	}
+
+	var nilAges map[string]int
+	if maps.Clone(nilAges) != nil {
+		t.Error("Clone(nil) should stay nil")
+	}
+}
+
+func TestMapsEqual(t *testing.T) {
+	nameToAge := map[string]int{"Bob": 42, "Alice": 33}
+	tests := []struct {
+		name  string
+		other map[string]int
+		want  bool
+	}{
+		{"same, different order", map[string]int{"Alice": 33, "Bob": 42}, true},
+		{"different value", map[string]int{"Alice": 33, "Bob": 43}, false},
+		{"missing key", map[string]int{"Alice": 33}, false},
+		{"extra key", map[string]int{"Alice": 33, "Bob": 42, "Cindy": 27}, false},
+		{"zero value vs missing", map[string]int{"Alice": 33, "Bob": 42, "Zed": 0}, false},
+	}
+	for _, test := range tests {
+		if got := maps.Equal(nameToAge, test.other); got != test.want {
+			t.Errorf("%s: Equal = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	var nilAges map[string]int
+	if !maps.Equal(nilAges, map[string]int{}) {
+		t.Error("nil and empty maps should be Equal")
+	}
+}
+
+func TestMapsKeysAndValuesSorted(t *testing.T) {
+	nameToAge := map[string]int{"Bob": 42, "Alice": 33, "Cindy": 27}
+	// Map order is random, so run it a few times, Sorted must always win.
+	for run := 0; run < 10; run++ {
+		if got := slices.Sorted(maps.Keys(nameToAge)); !slices.Equal(got, []string{"Alice", "Bob", "Cindy"}) {
+			t.Fatalf("sorted keys = %q", got)
+		}
+		if got := slices.Sorted(maps.Values(nameToAge)); !slices.Equal(got, []int{27, 33, 42}) {
+			t.Fatalf("sorted values = %v", got)
+		}
+	}
+	if got := slices.Collect(maps.Keys(map[string]int{})); len(got) != 0 {
+		t.Errorf("keys of an empty map = %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func main() {
+	// The intro shows two ways to make an "empty" slice:
+	var nilSlice []int          // nil slice, no array behind it yet
+	emptySlice := []int{}       // empty slice, points at a real (zero length) array
+	madeSlice := make([]int, 0) // same as []int{}
+
+	// Most of the time they act exactly the same.
+	fmt.Println(len(nilSlice), len(emptySlice), len(madeSlice)) // 0 0 0
+	fmt.Println(cap(nilSlice), cap(emptySlice))                 // 0 0
+
+	for range nilSlice {
+		fmt.Println("never runs, ranging over nil is fine")
+	}
+
+	// Appending to a nil slice works, append allocates for you.
+	// That's why "var s []int" then append in a loop is the common style.
+	nilSlice = append(nilSlice, 1, 2, 3)
+	fmt.Println(nilSlice) // [1 2 3]
+
+	// Difference 1: == nil
+	var stillNil []int
+	fmt.Println(stillNil == nil, emptySlice == nil) // true false
+
+	// Difference 2: JSON. This is the one that bites.
+	// A JS frontend doing response.users.length crashes on null.
+	nilJSON, _ := json.Marshal(stillNil)
+	emptyJSON, _ := json.Marshal(emptySlice)
+	fmt.Println(string(nilJSON), string(emptyJSON)) // null []
+
+	// Same inside a struct, e.g. an API response with no results.
+	type response struct {
+		Users []string `json:"users"`
+	}
+	noUsersJSON, _ := json.Marshal(response{})
+	fmt.Println(string(noUsersJSON)) // {"users":null}
+
+	emptyUsersJSON, _ := json.Marshal(response{Users: []string{}})
+	fmt.Println(string(emptyUsersJSON)) // {"users":[]}
+
+	// Rule of thumb:
+	//   - check emptiness with len(s) == 0, never s == nil (that misses []int{})
+	//   - use var s []T inside your code
+	//   - use []T{} (or make) for anything about to be sent as JSON, when clients expect []
+
+	// Nil maps
+	//
+	// Maps are NOT like slices here. The intro declares "var aMap map[string]int",
+	// reading from it is fine...
+	var aMap map[string]int
+	fmt.Println(aMap["x"], len(aMap)) // 0 0, the zero value, no panic
+
+	value, ok := aMap["x"]
+	fmt.Println(value, ok) // 0 false
+
+	// ...but WRITING to it panics. There's no append-style helper to allocate for you.
+	fmt.Println(writePanics(aMap)) // true, panic: assignment to entry in nil map
+
+	// The fix: always make the map (or use a literal) before writing.
+	aMap = make(map[string]int)    // or map[string]int{}
+	fmt.Println(writePanics(aMap)) // false
+	fmt.Println(aMap["x"])         // 1
+
+	// Inside structs it's easy to forget, so give the struct a constructor
+	// that makes the map, and use that everywhere instead of the literal.
+	//
+	//	type cache struct { items map[string]int }
+	//	func newCache() *cache { return &cache{items: map[string]int{}} }
+}
+
+// writePanics tries m["x"] = 1, and reports whether it panicked.
+//
+// recover() inside a deferred func catches the panic, so we can show it
+// without the program crashing. (Don't do this to paper over real bugs.)
+func writePanics(m map[string]int) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("recovered:", r)
+			panicked = true // named return value, so the deferred func can set it
+		}
+	}()
+
+	m["x"] = 1
+	return false
+}
@@ -0,0 +1,98 @@
+package main
+
+//	go test go_21_nilslice.go go_21_nilslice_test.go
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestNilVsEmptySliceJSON(t *testing.T) {
+	type response struct {
+		Users []string `json:"users"`
+	}
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil slice", []int(nil), `null`},
+		{"empty slice", []int{}, `[]`},
+		{"made slice", make([]int, 0), `[]`},
+		{"nil in a struct", response{}, `{"users":null}`},
+		{"empty in a struct", response{Users: []string{}}, `{"users":[]}`},
+	}
+	for _, test := range tests {
+		data, err := json.Marshal(test.value)
+		if err != nil || string(data) != test.want {
+			t.Errorf("%s: Marshal = %s, %v, want %s", test.name, data, err, test.want)
+		}
+	}
+}
+
+func TestNilVsEmptySliceCompare(t *testing.T) {
+	var nilSlice []int
+	emptySlice := []int{}
+	if nilSlice != nil || emptySlice == nil {
+		t.Error("want only the var slice to be == nil")
+	}
+	if len(nilSlice) != 0 || len(emptySlice) != 0 {
+		t.Error("want both to have len 0, that's the check to use")
+	}
+	if !slices.Equal(nilSlice, emptySlice) {
+		t.Error("slices.Equal treats nil and empty as the same")
+	}
+
+	var decoded []int // and coming back from JSON, null stays nil
+	if err := json.Unmarshal([]byte(`null`), &decoded); err != nil || decoded != nil {
+		t.Errorf("Unmarshal(null) = %v, %v, want nil", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`[]`), &decoded); err != nil || decoded == nil {
+		t.Errorf("Unmarshal([]) = %#v, %v, want empty but not nil", decoded, err)
+	}
+}
+
+func TestAppendToNilSlice(t *testing.T) {
+	var numbers []int
+	for i := 1; i <= 3; i++ {
+		numbers = append(numbers, i)
+	}
+	if !slices.Equal(numbers, []int{1, 2, 3}) || numbers == nil {
+		t.Errorf("numbers = %v, want [1 2 3]", numbers)
+	}
+
+	var more []int
+	more = append(more, numbers...)
+	more[0] = 100
+	if numbers[0] != 1 {
+		t.Error("append to nil made a new array, editing it shouldn't touch numbers")
+	}
+}
+
+func TestNilMap(t *testing.T) {
+	var aMap map[string]int
+
+	// Reading is fine, and gets the zero value.
+	if value, ok := aMap["x"]; value != 0 || ok || len(aMap) != 0 {
+		t.Errorf("read from nil map = %d, %v, len %d, want 0, false, 0", value, ok, len(aMap))
+	}
+	for range aMap {
+		t.Error("ranging over a nil map should do nothing")
+	}
+	delete(aMap, "x") // even delete is fine, there's nothing to delete
+
+	// Writing panics.
+	if !writePanics(aMap) {
+		t.Error("writing to a nil map didn't panic")
+	}
+
+	// make fixes it.
+	aMap = make(map[string]int)
+	if writePanics(aMap) {
+		t.Error("writing to a made map panicked")
+	}
+	if aMap["x"] != 1 {
+		t.Errorf("aMap[x] = %d, want 1", aMap["x"])
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Sender is the same interface as SenderInterface in go_4_structs_interfaces.go.
+type Sender interface {
+	Send(message string) error
+}
+
+// ValueSender has a value receiver, like SenderA.
+type ValueSender struct {
+	Name string
+}
+
+func (s ValueSender) Send(message string) error {
+	fmt.Printf("value sender %s: %s\n", s.Name, message)
+	return nil
+}
+
+// PointerSender has a pointer receiver, like SenderB.
+type PointerSender struct {
+	Name  string
+	Count int
+}
+
+func (s *PointerSender) Send(message string) error {
+	s.Count++
+	fmt.Printf("pointer sender %s #%d: %s\n", s.Name, s.Count, message)
+	return nil
+}
+
+// The "method set" rules, which types satisfy Sender?
+//
+//	               | value receiver methods | pointer receiver methods
+//	---------------+------------------------+-------------------------
+//	ValueSender    | ✅                      | (has none)
+//	*ValueSender   | ✅ Go derefs for you     | (has none)
+//	PointerSender  | (has none)              | ❌ NOT in its method set
+//	*PointerSender | (has none)              | ✅
+//
+// A pointer gets every method. A plain value only gets the value receiver ones.
+//
+// These lines do nothing at runtime, but the compiler checks them, so they're a
+// cheap way to prove (and document) that a type satisfies an interface.
+var (
+	_ Sender = ValueSender{}         // ✅ value has the value method
+	_ Sender = &ValueSender{}        // ✅ pointer has it too
+	_ Sender = &PointerSender{}      // ✅ pointer has the pointer method
+	_ Sender = (*PointerSender)(nil) // ✅ same check, without making a PointerSender
+	// _ Sender = PointerSender{}   <-- ❌ compile error: PointerSender does not implement Sender (method Send has pointer receiver)
+)
+
+func sendVia(sender Sender, message string) {
+	_ = sender.Send(message)
+}
+
+func main() {
+	// Why the rule? An interface holds a COPY of whatever you put in it.
+	//
+	// If sendVia(pointerSender) were allowed, Send would run on sendVia's copy,
+	// our Count would never go up, and we'd have the SenderA bug all over again but silently.
+	// Go refuses to compile it instead. That's why go_4 calls SendEmail(ctx, &senderB, ...).
+	valueSender := ValueSender{Name: "A"}
+	pointerSender := PointerSender{Name: "B"}
+
+	sendVia(valueSender, "by value")      // ✅ value sender A: by value
+	sendVia(&valueSender, "by pointer")   // ✅ also fine
+	sendVia(&pointerSender, "by pointer") // ✅ pointer sender B #1
+	// sendVia(pointerSender, "by value") <-- ❌ compile error, see the table above
+	fmt.Println(pointerSender.Count) // 1
+
+	// Calling methods directly is friendlier than interfaces.
+	// pointerSender is a variable, so it's "addressable", Go quietly does (&pointerSender).Send
+	_ = pointerSender.Send("direct call") // pointer sender B #2
+	fmt.Println(pointerSender.Count)      // 2
+
+	// Some values are NOT addressable, they have no home in memory to point at:
+	// map entries and function return values. Pointer methods can't be called on those.
+	senders := map[string]PointerSender{"C": {Name: "C"}}
+	// senders["C"].Send("hi")       <-- ❌ compile error: cannot call pointer method Send on PointerSender
+	// newPointerSender().Send("hi") <-- ❌ same thing, a return value
+
+	// The fix: copy it into a variable, or store pointers in the map.
+	senderC := senders["C"]
+	_ = senderC.Send("from a variable") // pointer sender C #1
+	fmt.Println(senders["C"].Count)     // 0, the map still has the old copy!
+
+	senderPointers := map[string]*PointerSender{"D": {Name: "D"}}
+	_ = senderPointers["D"].Send("from a map of pointers") // pointer sender D #1
+	fmt.Println(senderPointers["D"].Count)                 // 1 ✅
+
+	// Value methods work everywhere, addressable or not.
+	_ = map[string]ValueSender{"E": {Name: "E"}}["E"].Send("from a map") // value sender E: from a map
+
+	// Nil receivers.
+	//
+	// A pointer method is just a function with the pointer as its first argument,
+	// (*Tree).Sum(t). Nothing stops t being nil! It only crashes if the method
+	// touches t.Something. In python, None.sum() fails before sum even runs.
+	//
+	//	   1
+	//	  / \
+	//	 2   3
+	tree := &Tree{Value: 1, Left: &Tree{Value: 2}, Right: &Tree{Value: 3}}
+	fmt.Println(tree.Sum()) // 6
+
+	var empty *Tree          // nil
+	fmt.Println(empty.Sum()) // 0, ✅ no panic, Sum checks for nil
+
+	fmt.Println(panics(func() { tree.RootValue() }))  // false
+	fmt.Println(panics(func() { empty.RootValue() })) // true, ❌ nil pointer dereference
+}
+
+// Tree is a binary tree, nil means "no tree".
+type Tree struct {
+	Value       int
+	Left, Right *Tree
+}
+
+// Sum works on a nil *Tree, it checks before touching any fields.
+// That makes the recursion simple, no "if t.Left != nil" everywhere,
+// just call Sum on the children and let the nil ones return 0.
+// Generated protobuf code does this for every field, msg.GetName() is "" when msg is nil.
+// It's not the norm though, so say so in the doc comment when a method is nil safe.
+func (t *Tree) Sum() int {
+	if t == nil {
+		return 0
+	}
+	return t.Value + t.Left.Sum() + t.Right.Sum()
+}
+
+// RootValue reads t.Value without checking, ❌ panics on a nil *Tree.
+func (t *Tree) RootValue() int {
+	return t.Value
+}
+
+// panics reports whether fn panicked.
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
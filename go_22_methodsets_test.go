@@ -0,0 +1,106 @@
+package main
+
+//	go test go_22_methodsets.go go_22_methodsets_test.go
+
+import "testing"
+
+func TestMethodSets(t *testing.T) {
+	// The compile time checks in go_22 can't be tested by running anything,
+	// but a type assertion asks the same question at runtime.
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{"ValueSender", ValueSender{}, true},
+		{"*ValueSender", &ValueSender{}, true},
+		{"PointerSender", PointerSender{}, false}, // Send isn't in a plain value's method set
+		{"*PointerSender", &PointerSender{}, true},
+	}
+	for _, test := range tests {
+		if _, ok := test.value.(Sender); ok != test.want {
+			t.Errorf("%s is a Sender = %v, want %v", test.name, ok, test.want)
+		}
+	}
+}
+
+func TestPointerSenderThroughAnInterface(t *testing.T) {
+	senderB := PointerSender{Name: "B"}
+	sendVia(&senderB, "one")
+	sendVia(&senderB, "two")
+	if senderB.Count != 2 {
+		t.Errorf("Count = %d, want 2, Send ran on our senderB, not a copy", senderB.Count)
+	}
+}
+
+func TestAddressableValues(t *testing.T) {
+	// A variable is addressable, so senderB.Send is really (&senderB).Send.
+	senderB := PointerSender{Name: "B"}
+	_ = senderB.Send("direct")
+	if senderB.Count != 1 {
+		t.Errorf("variable: Count = %d, want 1", senderB.Count)
+	}
+
+	// So is a slice element, it lives in the slice's array.
+	senders := []PointerSender{{Name: "S"}}
+	_ = senders[0].Send("from a slice")
+	if senders[0].Count != 1 {
+		t.Errorf("slice element: Count = %d, want 1", senders[0].Count)
+	}
+}
+
+func TestNonAddressableValues(t *testing.T) {
+	// A map entry isn't addressable, senders["C"].Send doesn't compile.
+	// Copying it out first works, but only changes the copy.
+	senders := map[string]PointerSender{"C": {Name: "C"}}
+	senderC := senders["C"]
+	_ = senderC.Send("from a variable")
+	if senderC.Count != 1 || senders["C"].Count != 0 {
+		t.Errorf("copy Count = %d, map Count = %d, want 1 and 0", senderC.Count, senders["C"].Count)
+	}
+	senders["C"] = senderC // put it back by hand
+	if senders["C"].Count != 1 {
+		t.Errorf("after storing the copy back: Count = %d, want 1", senders["C"].Count)
+	}
+
+	// A map of pointers doesn't have the problem, the entry IS a pointer.
+	senderPointers := map[string]*PointerSender{"D": {Name: "D"}}
+	_ = senderPointers["D"].Send("from a map of pointers")
+	if senderPointers["D"].Count != 1 {
+		t.Errorf("map of pointers: Count = %d, want 1", senderPointers["D"].Count)
+	}
+
+	// Value methods work on anything, addressable or not.
+	if err := map[string]ValueSender{"E": {Name: "E"}}["E"].Send("from a map"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNilReceivers(t *testing.T) {
+	var empty *Tree
+	if got := empty.Sum(); got != 0 {
+		t.Errorf("nil Sum = %d, want 0", got)
+	}
+	if !panics(func() { empty.RootValue() }) {
+		t.Error("nil RootValue didn't panic")
+	}
+
+	//	   1
+	//	  / \
+	//	 2   3
+	//	      \
+	//	       4
+	tree := &Tree{Value: 1, Left: &Tree{Value: 2}, Right: &Tree{Value: 3, Right: &Tree{Value: 4}}}
+	if got := tree.Sum(); got != 10 {
+		t.Errorf("Sum = %d, want 10, the nil children count as 0", got)
+	}
+	if panics(func() { tree.RootValue() }) {
+		t.Error("RootValue on a real tree panicked")
+	}
+
+	// A nil *Tree in an interface is NOT a nil interface, the classic Go gotcha.
+	var summer interface{ Sum() int } = empty
+	if summer == nil || summer.Sum() != 0 {
+		t.Error("a nil *Tree in an interface should be non-nil, and Sum still works")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+)
+
+// Message is something waiting to be sent, higher Priority goes first.
+type Message struct {
+	Text     string
+	Priority int
+
+	index int // where it sits in the heap, kept up to date by Swap/Push/Pop, needed by Update
+}
+
+// PriorityQueue pops the highest priority Message first, python's heapq (but max first).
+//
+// container/heap doesn't give you a heap type, it gives you heap *functions*
+// that work on anything implementing heap.Interface:
+//
+//	sort.Interface (Len, Less, Swap, see go_24_sort.go)
+//	Push(x any)   // add x at the end
+//	Pop() any     // remove and return the last item
+//
+// heap.Push / heap.Pop call these, then shuffle things around to
+// keep the heap in order. Always call heap.Push(pq, x), never pq.Push(x) directly!
+type PriorityQueue []*Message
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+
+// Less is "greater than" on purpose, so the highest priority ends up on top.
+func (pq PriorityQueue) Less(i, j int) bool { return pq[i].Priority > pq[j].Priority }
+
+func (pq PriorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+// Push and Pop change the length, so they need a pointer receiver.
+func (pq *PriorityQueue) Push(x any) {
+	message := x.(*Message)
+	message.index = len(*pq)
+	*pq = append(*pq, message)
+}
+
+func (pq *PriorityQueue) Pop() any {
+	old := *pq
+	last := old[len(old)-1]
+	old[len(old)-1] = nil // let the garbage collector have it
+	last.index = -1       // not in the queue anymore
+	*pq = old[:len(old)-1]
+	return last
+}
+
+// Update changes a message's priority, and moves it to its new spot.
+func (pq *PriorityQueue) Update(message *Message, priority int) {
+	message.Priority = priority
+	heap.Fix(pq, message.index) // cheaper than removing and pushing again
+}
+
+func main() {
+	pq := &PriorityQueue{}
+	heap.Init(pq)
+
+	// Added in any old order...
+	heap.Push(pq, &Message{Text: "newsletter", Priority: 1})
+	heap.Push(pq, &Message{Text: "password reset", Priority: 10})
+	lunch := &Message{Text: "lunch order", Priority: 3}
+	heap.Push(pq, lunch)
+	heap.Push(pq, &Message{Text: "invoice", Priority: 5})
+
+	// Lunch is getting urgent.
+	pq.Update(lunch, 7)
+
+	// ...come out highest priority first.
+	for pq.Len() > 0 {
+		message := heap.Pop(pq).(*Message) // Pop returns any, assert back to *Message
+		fmt.Println(message.Priority, message.Text)
+	}
+	// 10 password reset
+	// 7 lunch order
+	// 5 invoice
+	// 1 newsletter
+
+	// container/list, a doubly linked list
+	//
+	// Each item is an *list.Element with Next() and Prev() pointers.
+	// When does it beat a slice?
+	//   - removing/inserting in the MIDDLE is O(1) if you hold the Element,
+	//     a slice has to shift everything after it over (O(n))
+	//   - Elements never move, so pointers to them stay valid
+	//     (the LRU cache in go_13_caching.go keeps them in a map for exactly this)
+	//
+	// Otherwise use a slice. Lists are slower to walk (items are scattered in
+	// memory) and aren't typed, Value is an any.
+	outbox := list.New()
+	invoiceElement := outbox.PushBack("invoice")
+	outbox.PushBack("newsletter")
+	outbox.PushFront("password reset") // jump the line
+	lunchElement := outbox.PushBack("lunch order")
+	outbox.InsertBefore("reminder", lunchElement)
+	// password reset, invoice, newsletter, reminder, lunch order
+
+	// The invoice was sent some other way, take it out of the MIDDLE.
+	// O(1) because we kept its Element, nothing after it moves.
+	// A slice would shift newsletter, reminder and lunch order all down one.
+	outbox.Remove(invoiceElement)
+
+	// Walk it front to back.
+	for e := outbox.Front(); e != nil; e = e.Next() {
+		fmt.Println(e.Value.(string)) // Value is an any, assert back to string
+	}
+	// password reset
+	// newsletter
+	// reminder
+	// lunch order
+	fmt.Println(outbox.Len()) // 4
+}
@@ -0,0 +1,129 @@
+package main
+
+//	go test go_23_heap.go go_23_heap_test.go
+
+import (
+	"container/heap"
+	"container/list"
+	"slices"
+	"testing"
+)
+
+// popAll pops pq until it's empty and returns the texts in the order they came out.
+func popAll(pq *PriorityQueue) []string {
+	var texts []string
+	for pq.Len() > 0 {
+		texts = append(texts, heap.Pop(pq).(*Message).Text)
+	}
+	return texts
+}
+
+func TestPriorityQueuePopOrder(t *testing.T) {
+	messages := map[string]int{"newsletter": 1, "lunch order": 3, "invoice": 5, "password reset": 10}
+	want := []string{"password reset", "invoice", "lunch order", "newsletter"}
+
+	// Every insertion order comes out the same.
+	for _, order := range [][]string{
+		{"newsletter", "lunch order", "invoice", "password reset"}, // lowest first
+		{"password reset", "invoice", "lunch order", "newsletter"}, // already in order
+		{"invoice", "newsletter", "password reset", "lunch order"}, // shuffled
+	} {
+		pq := &PriorityQueue{}
+		for _, text := range order {
+			heap.Push(pq, &Message{Text: text, Priority: messages[text]})
+		}
+		if got := popAll(pq); !slices.Equal(got, want) {
+			t.Errorf("pushed %q, popped %q, want %q", order, got, want)
+		}
+	}
+}
+
+func TestPriorityQueueInit(t *testing.T) {
+	// A slice that's already full can be made into a heap in one go.
+	pq := &PriorityQueue{
+		{Text: "low", Priority: 1},
+		{Text: "high", Priority: 9},
+		{Text: "middle", Priority: 5},
+	}
+	for i, message := range *pq {
+		message.index = i // heap.Init only calls Swap, it needs these to start out right
+	}
+	heap.Init(pq)
+	if got := popAll(pq); !slices.Equal(got, []string{"high", "middle", "low"}) {
+		t.Errorf("popped %q", got)
+	}
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	pq := &PriorityQueue{}
+	lunch := &Message{Text: "lunch order", Priority: 3}
+	newsletter := &Message{Text: "newsletter", Priority: 1}
+	for _, message := range []*Message{{Text: "invoice", Priority: 5}, lunch, newsletter, {Text: "password reset", Priority: 10}} {
+		heap.Push(pq, message)
+	}
+
+	pq.Update(lunch, 7)       // up, past the invoice
+	pq.Update(newsletter, 20) // up, all the way to the top
+	if got := popAll(pq); !slices.Equal(got, []string{"newsletter", "password reset", "lunch order", "invoice"}) {
+		t.Errorf("popped %q after the updates", got)
+	}
+	if lunch.index != -1 {
+		t.Errorf("popped message index = %d, want -1", lunch.index)
+	}
+}
+
+func TestPriorityQueueUpdateDown(t *testing.T) {
+	pq := &PriorityQueue{}
+	reset := &Message{Text: "password reset", Priority: 10}
+	for _, message := range []*Message{reset, {Text: "invoice", Priority: 5}, {Text: "newsletter", Priority: 1}} {
+		heap.Push(pq, message)
+	}
+	pq.Update(reset, 0) // down from the top, to the bottom
+	if got := popAll(pq); !slices.Equal(got, []string{"invoice", "newsletter", "password reset"}) {
+		t.Errorf("popped %q after lowering the top one", got)
+	}
+}
+
+// listValues walks l front to back.
+func listValues(l *list.List) []string {
+	var values []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value.(string))
+	}
+	return values
+}
+
+func TestOutboxList(t *testing.T) {
+	outbox := list.New()
+	invoice := outbox.PushBack("invoice")
+	outbox.PushBack("newsletter")
+	outbox.PushFront("password reset")
+	lunch := outbox.PushBack("lunch order")
+	outbox.InsertBefore("reminder", lunch)
+
+	want := []string{"password reset", "invoice", "newsletter", "reminder", "lunch order"}
+	if got := listValues(outbox); !slices.Equal(got, want) {
+		t.Fatalf("after the inserts: %q, want %q", got, want)
+	}
+
+	outbox.Remove(invoice) // from the middle
+	want = []string{"password reset", "newsletter", "reminder", "lunch order"}
+	if got := listValues(outbox); !slices.Equal(got, want) || outbox.Len() != 4 {
+		t.Errorf("after removing invoice: %q (Len %d), want %q", got, outbox.Len(), want)
+	}
+
+	// The other Elements weren't touched, lunch is still a valid handle.
+	outbox.MoveToFront(lunch)
+	if got := outbox.Front().Value; got != "lunch order" {
+		t.Errorf("Front = %v after MoveToFront, want lunch order", got)
+	}
+
+	// And backwards, Prev is the other direction.
+	var backwards []string
+	for e := outbox.Back(); e != nil; e = e.Prev() {
+		backwards = append(backwards, e.Value.(string))
+	}
+	if want := []string{"reminder", "newsletter", "password reset", "lunch order"}; !slices.Equal(backwards, want) {
+		t.Errorf("back to front: %q, want %q", backwards, want)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// student is the same struct from the intro's slices section.
+type student struct {
+	year int
+	name string
+}
+
+// ******************************************************************************************************
+// ******************************************************************************************************
+// sort.Interface
+// ******************************************************************************************************
+// ******************************************************************************************************
+
+// ByYear sorts students by year, youngest year first.
+//
+// Before sort.Slice (Go 1.8) this was the only way to sort your own types.
+// You make a new slice type and give it the three methods of sort.Interface:
+//
+//	type Interface interface {
+//	  Len() int           // how many items
+//	  Less(i, j int) bool // should item i come before item j?
+//	  Swap(i, j int)      // swap items i and j
+//	}
+//
+// sort.Sort only ever talks to your data through those three methods,
+// so it can sort anything: slices, a linked list, rows in a file...
+// Python's sorted(students, key=lambda s: s.year) is the same idea, with one function.
+type ByYear []student
+
+func (s ByYear) Len() int           { return len(s) }
+func (s ByYear) Less(i, j int) bool { return s[i].year < s[j].year }
+func (s ByYear) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func main() {
+	nameYearSlice := []student{
+		{5, "cindy"},
+		{2, "bob"},
+		{3, "alice"},
+	}
+
+	// ByYear(nameYearSlice) is a conversion, not a copy, same underlying array.
+	// So sorting it sorts nameYearSlice in place.
+	sort.Sort(ByYear(nameYearSlice))
+	fmt.Println(nameYearSlice) // [{2 bob} {3 alice} {5 cindy}]
+
+	// Reverse flips Less for you, any sort.Interface works.
+	sort.Sort(sort.Reverse(ByYear(nameYearSlice)))
+	fmt.Println(nameYearSlice) // [{5 cindy} {3 alice} {2 bob}]
+
+	// sort.Slice is the shortcut used in the intro. Under the hood it's the same
+	// algorithm, Len and Swap come from the slice itself, and your func is Less.
+	sort.Slice(nameYearSlice, func(i, j int) bool {
+		return nameYearSlice[i].name < nameYearSlice[j].name // by name this time
+	})
+	fmt.Println(nameYearSlice) // [{3 alice} {2 bob} {5 cindy}]
+
+	fmt.Println(sort.IsSorted(ByYear(nameYearSlice))) // false, it's sorted by name now
+
+	// Writing our own, checked against the usual tricky inputs.
+	cases := [][]int{
+		{1, 2, 3, 4, 5},    // already sorted
+		{5, 4, 3, 2, 1},    // reverse sorted, worst case for a naive quicksort
+		{3, 1, 3, 2, 1, 3}, // duplicates
+		{},                 // empty
+		{42},               // one item
+	}
+	for _, numbers := range cases {
+		merged := mergeSort(numbers) // numbers is untouched, merged is new
+
+		quick := slices.Clone(numbers)
+		quickSort(quick) // sorts quick in place
+
+		fmt.Println(merged, quick, slices.IsSorted(merged) && slices.Equal(merged, quick))
+	}
+	// [1 2 3 4 5] [1 2 3 4 5] true
+	// [1 2 3 4 5] [1 2 3 4 5] true
+	// [1 1 2 3 3 3] [1 1 2 3 3 3] true
+	// [] [] true
+	// [42] [42] true
+
+	// For real code use slices.Sort(numbers) or sort.Ints(numbers). It's pdqsort,
+	// a quicksort that switches to insertion sort for small pieces and heapsort
+	// when the splits go badly, so it never hits the O(n²) worst case.
+	// slices.SortStableFunc is the one to use when you need a stable sort like merge sort.
+}
+
+// ******************************************************************************************************
+// ******************************************************************************************************
+// Merge sort and quicksort
+// ******************************************************************************************************
+// ******************************************************************************************************
+
+// mergeSort returns a sorted copy of s, s isn't changed. O(n log n) every time.
+//
+// Split in half, sort each half (recursion!), merge the two sorted halves.
+// The halves are s[:mid] and s[mid:], slices of the same array (go_1_intro.go),
+// so splitting is free. Merging is what allocates: each merge makes a new
+// slice, O(n) extra memory per level. That's the price for being simple
+// and "stable" (equal items keep their original order).
+func mergeSort(s []int) []int {
+	if len(s) <= 1 {
+		return slices.Clone(s) // still a copy, so the caller can always change the result safely
+	}
+
+	mid := len(s) / 2
+	left := mergeSort(s[:mid])
+	right := mergeSort(s[mid:])
+
+	merged := make([]int, 0, len(s)) // we know the final size, so append never has to grow it
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		if left[i] <= right[j] { // <= not <, takes from the left on ties, that's what makes it stable
+			merged = append(merged, left[i])
+			i++
+		} else {
+			merged = append(merged, right[j])
+			j++
+		}
+	}
+	// One side ran out, the rest of the other is already sorted.
+	merged = append(merged, left[i:]...)
+	return append(merged, right[j:]...)
+}
+
+// quickSort sorts s in place. No allocations, O(n log n) on average.
+//
+// Pick a pivot, move everything smaller to its left, then sort each side.
+// Because sub-slices share the array, sorting s[:p] really sorts part of s,
+// nothing is ever copied.
+// The catch: a bad pivot splits n items into n-1 and 0, and it gets O(n²).
+// Always picking the last item does exactly that on sorted input, so we
+// take the middle one instead.
+func quickSort(s []int) {
+	if len(s) <= 1 {
+		return
+	}
+
+	// Move the middle item to the end, and use it as the pivot.
+	mid := len(s) / 2
+	last := len(s) - 1
+	s[mid], s[last] = s[last], s[mid]
+	pivot := s[last]
+
+	// Everything before p is < pivot.
+	p := 0
+	for i := 0; i < last; i++ {
+		if s[i] < pivot {
+			s[i], s[p] = s[p], s[i]
+			p++
+		}
+	}
+	s[p], s[last] = s[last], s[p] // pivot goes to its final spot
+
+	quickSort(s[:p])
+	quickSort(s[p+1:])
+}
@@ -0,0 +1,114 @@
+package main
+
+//	go test go_24_sort.go go_24_sort_test.go
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestSortByYear(t *testing.T) {
+	students := []student{{5, "cindy"}, {2, "bob"}, {3, "alice"}}
+
+	sort.Sort(ByYear(students))
+	if want := []student{{2, "bob"}, {3, "alice"}, {5, "cindy"}}; !slices.Equal(students, want) {
+		t.Errorf("sorted by year = %v, want %v", students, want)
+	}
+	if !sort.IsSorted(ByYear(students)) {
+		t.Error("IsSorted = false right after sorting")
+	}
+
+	sort.Sort(sort.Reverse(ByYear(students)))
+	if want := []student{{5, "cindy"}, {3, "alice"}, {2, "bob"}}; !slices.Equal(students, want) {
+		t.Errorf("reversed = %v, want %v", students, want)
+	}
+}
+
+func TestSortByYearStable(t *testing.T) {
+	// Same year, so only a stable sort promises to keep the original order.
+	students := []student{{3, "cindy"}, {1, "dave"}, {3, "alice"}, {1, "bob"}}
+	sort.Stable(ByYear(students))
+	want := []student{{1, "dave"}, {1, "bob"}, {3, "cindy"}, {3, "alice"}}
+	if !slices.Equal(students, want) {
+		t.Errorf("stable by year = %v, want %v", students, want)
+	}
+}
+
+func TestSortSliceMatchesSortInterface(t *testing.T) {
+	// sort.Slice is sort.Sort with Len and Swap filled in for you.
+	viaInterface := []student{{5, "cindy"}, {2, "bob"}, {3, "alice"}, {4, "dave"}}
+	viaSlice := slices.Clone(viaInterface)
+
+	sort.Sort(ByYear(viaInterface))
+	sort.Slice(viaSlice, func(i, j int) bool { return viaSlice[i].year < viaSlice[j].year })
+	if !slices.Equal(viaInterface, viaSlice) {
+		t.Errorf("sort.Sort = %v, sort.Slice = %v, want the same", viaInterface, viaSlice)
+	}
+}
+
+func TestMergeSortAndQuickSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"already sorted", []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}},
+		{"reverse sorted", []int{5, 4, 3, 2, 1}, []int{1, 2, 3, 4, 5}},
+		{"duplicates", []int{3, 1, 3, 2, 1, 3}, []int{1, 1, 2, 3, 3, 3}},
+		{"all the same", []int{7, 7, 7, 7}, []int{7, 7, 7, 7}},
+		{"negatives", []int{0, -5, 3, -1}, []int{-5, -1, 0, 3}},
+		{"one item", []int{42}, []int{42}},
+		{"two items", []int{2, 1}, []int{1, 2}},
+		{"empty", []int{}, []int{}},
+		{"nil", nil, []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := slices.Clone(test.input)
+
+			if got := mergeSort(input); !slices.Equal(got, test.want) {
+				t.Errorf("mergeSort = %v, want %v", got, test.want)
+			}
+			if !slices.Equal(input, test.input) {
+				t.Errorf("mergeSort changed its input to %v", input)
+			}
+
+			quickSort(input)
+			if !slices.Equal(input, test.want) {
+				t.Errorf("quickSort = %v, want %v", input, test.want)
+			}
+		})
+	}
+}
+
+func TestMergeSortReturnsACopy(t *testing.T) {
+	for _, input := range [][]int{{1}, {3, 2, 1}} {
+		sorted := mergeSort(input)
+		sorted[0] = 100
+		if input[0] == 100 {
+			t.Errorf("editing mergeSort's result changed its input %v", input)
+		}
+	}
+}
+
+func TestSortsAgreeWithSlicesSort(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2)) // fixed seed, the same "random" input every run
+	for run := 0; run < 50; run++ {
+		input := make([]int, random.IntN(200))
+		for i := range input {
+			input[i] = random.IntN(50) // small range, lots of duplicates
+		}
+		want := slices.Clone(input)
+		slices.Sort(want)
+
+		if got := mergeSort(input); !slices.Equal(got, want) {
+			t.Fatalf("mergeSort(%v) = %v", input, got)
+		}
+		quickSort(input)
+		if !slices.Equal(input, want) {
+			t.Fatalf("quickSort gave %v, want %v", input, want)
+		}
+	}
+}
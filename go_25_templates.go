@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template" // both packages are called template, so rename one
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// SenderB is the same struct from go_4_structs_interfaces.go.
+type SenderB struct {
+	FirstName    string
+	MessageCount int
+}
+
+// Name is a method, templates can call those too, {{.Name}} works for
+// a field OR a method that takes no arguments.
+func (s SenderB) Name() string {
+	return s.FirstName
+}
+
+// text/template is Go's built in templating, like python's jinja2 or JS template literals.
+//
+//	{{.Name}}             the Name field/method of whatever we passed in ("." is "the data")
+//	{{range .}}...{{end}} loop over a slice, inside the loop "." is the current item
+//	{{if .X}}...{{end}}   only if .X isn't the zero value (0, "", nil, empty slice)
+//	{{plural .N "x"}}     call a function, with arguments separated by spaces, no commas or ()
+//
+// The {{- and -}} versions trim the whitespace (newlines too) on that side.
+var (
+	// template.Must panics if the template has a syntax error. Fine here, it's
+	// parsed once when the program starts, a typo is a bug to fix, not an error to handle.
+	greeting = template.Must(template.New("greeting").Parse(
+		"Hello {{.Name}}, you have {{.MessageCount}} messages\n",
+	))
+
+	// Funcs has to come BEFORE Parse, the parser checks every function exists.
+	summary = template.Must(template.New("summary").Funcs(template.FuncMap{
+		"plural": plural,
+		"upper":  strings.ToUpper, // any func returning 1 value (or a value and an error) works
+	}).Parse(
+		"{{range .}}" +
+			"{{upper .Name}}: {{plural .MessageCount \"message\"}}\n" +
+			"{{else}}" + // range has an else, for an empty slice
+			"no senders\n" +
+			"{{end}}",
+	))
+)
+
+// The same page, made by both packages.
+var (
+	textPage = template.Must(template.New("page").Parse(`<p>{{.}}</p>`))
+	htmlPage = htmltemplate.Must(htmltemplate.New("page").Parse(`<p>{{.}}</p>`))
+	htmlLink = htmltemplate.Must(htmltemplate.New("link").Parse(`<a href="{{.}}">profile</a>`))
+)
+
+// plural is 1 message, 2 messages, 0 messages.
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// render runs a template into a string.
+// Execute writes to any io.Writer, a bytes.Buffer collects it for us.
+func render(t *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func main() {
+	senderB := SenderB{FirstName: "B", MessageCount: 3}
+
+	// Straight to stdout.
+	if err := greeting.Execute(os.Stdout, senderB); err != nil { // Hello B, you have 3 messages
+		log.Fatal(err)
+	}
+
+	// Into a string, so we can check it.
+	out, err := render(greeting, senderB)
+	fmt.Println(out == "Hello B, you have 3 messages\n", err) // true <nil>
+
+	senders := []SenderB{
+		{FirstName: "alice", MessageCount: 1},
+		{FirstName: "bob", MessageCount: 0},
+		{FirstName: "cindy", MessageCount: 12},
+	}
+	out, err = render(summary, senders)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(out)
+	// ALICE: 1 message
+	// BOB: 0 messages
+	// CINDY: 12 messages
+
+	out, _ = render(summary, []SenderB{})
+	fmt.Print(out) // no senders
+
+	// Mistakes in the DATA only show up when it runs, as an error from Execute.
+	_, err = render(greeting, 42)
+	fmt.Println(err) // template: greeting:1:8: executing "greeting" at <.Name>: can't evaluate field Name in type int
+
+	// Careful with maps, {{.Name}} is a key lookup there, and a missing key is NOT an error.
+	out, _ = render(greeting, map[string]int{"MessageCount": 1})
+	fmt.Print(out) // Hello <no value>, you have 1 messages
+	// template.New("x").Option("missingkey=error") makes it an error instead.
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// html/template
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// A user typed this in as their message. If it goes on a page as is, it
+	// runs in every visitor's browser, that's XSS (cross site scripting).
+	evil := `<script>alert("stolen cookies")</script>`
+
+	// text/template doesn't know it's making HTML, it's just text, so it pastes it in.
+	var textBuf bytes.Buffer
+	if err := textPage.Execute(&textBuf, evil); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(textBuf.String()) // <p><script>alert("stolen cookies")</script></p>  ❌ script runs
+
+	// html/template has the SAME API, and escapes everything it inserts.
+	var htmlBuf bytes.Buffer
+	if err := htmlPage.Execute(&htmlBuf, evil); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(htmlBuf.String())                                     // <p>&lt;script&gt;alert(&#34;stolen cookies&#34;)&lt;/script&gt;</p>  ✅ shows as text
+	fmt.Println(strings.Contains(htmlBuf.String(), "<script>"))       // false
+	fmt.Println(strings.Contains(htmlBuf.String(), "&lt;script&gt;")) // true
+
+	// It's smarter than a find and replace, it escapes for WHERE the value goes.
+	// Inside href="..." it also blocks javascript: URLs, inside <script> it writes JS strings.
+	var linkBuf bytes.Buffer
+	if err := htmlLink.Execute(&linkBuf, `javascript:alert(1)`); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(linkBuf.String()) // <a href="#ZgotmplZ">profile</a>, html/template's "nope, unsafe URL" marker
+
+	// Rule: if the output is HTML, use html/template. python's jinja2 needs
+	// autoescape=True for this, Django templates do it by default like Go.
+}
@@ -0,0 +1,105 @@
+package main
+
+//	go test go_25_templates.go go_25_templates_test.go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGreeting(t *testing.T) {
+	tests := []struct {
+		sender SenderB
+		want   string
+	}{
+		{SenderB{FirstName: "B", MessageCount: 3}, "Hello B, you have 3 messages\n"},
+		{SenderB{FirstName: "alice"}, "Hello alice, you have 0 messages\n"},
+	}
+	for _, test := range tests {
+		got, err := render(greeting, test.sender)
+		if err != nil || got != test.want {
+			t.Errorf("render(%+v) = %q, %v, want %q", test.sender, got, err, test.want)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	senders := []SenderB{
+		{FirstName: "alice", MessageCount: 1},
+		{FirstName: "bob", MessageCount: 0},
+		{FirstName: "cindy", MessageCount: 12},
+	}
+	got, err := render(summary, senders)
+	want := "ALICE: 1 message\nBOB: 0 messages\nCINDY: 12 messages\n"
+	if err != nil || got != want {
+		t.Errorf("render = %q, %v, want %q", got, err, want)
+	}
+
+	for name, empty := range map[string][]SenderB{"empty": {}, "nil": nil} {
+		if got, err := render(summary, empty); err != nil || got != "no senders\n" {
+			t.Errorf("%s: render = %q, %v, want the else branch", name, got, err)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for n, want := range map[int]string{0: "0 messages", 1: "1 message", 2: "2 messages"} {
+		if got := plural(n, "message"); got != want {
+			t.Errorf("plural(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := render(greeting, 42); err == nil || !strings.Contains(err.Error(), "can't evaluate field Name") {
+		t.Errorf("render(42) err = %v, want a missing field error", err)
+	}
+	// A map with a missing key isn't an error, just "<no value>".
+	got, err := render(greeting, map[string]int{"MessageCount": 1})
+	if err != nil || got != "Hello <no value>, you have 1 messages\n" {
+		t.Errorf("render(map) = %q, %v", got, err)
+	}
+}
+
+func TestHTMLTemplateEscapes(t *testing.T) {
+	evil := `<script>alert("stolen cookies")</script>`
+
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textPage.Execute(&textBuf, evil); err != nil {
+		t.Fatal(err)
+	}
+	if err := htmlPage.Execute(&htmlBuf, evil); err != nil {
+		t.Fatal(err)
+	}
+
+	// text/template pastes it in as is.
+	if got := textBuf.String(); got != "<p>"+evil+"</p>" {
+		t.Errorf("text/template = %q, want the script untouched", got)
+	}
+
+	// html/template escapes every angle bracket and quote that came from the data,
+	// only the template's own <p> tags are left.
+	html := htmlBuf.String()
+	if want := `<p>&lt;script&gt;alert(&#34;stolen cookies&#34;)&lt;/script&gt;</p>`; html != want {
+		t.Errorf("html/template = %q, want %q", html, want)
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(html, "<p>"), "</p>")
+	if strings.ContainsAny(inner, `<>"`) {
+		t.Errorf("escaped value %q still has < > or \"", inner)
+	}
+}
+
+func TestHTMLTemplateUnsafeURL(t *testing.T) {
+	tests := []struct{ url, want string }{
+		{`javascript:alert(1)`, `<a href="#ZgotmplZ">profile</a>`},
+		{`https://example.com/alice?tab=1&x=2`, `<a href="https://example.com/alice?tab=1&amp;x=2">profile</a>`},
+		{`/users/"><script>`, `<a href="/users/%22%3e%3cscript%3e">profile</a>`},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := htmlLink.Execute(&buf, test.url); err != nil || buf.String() != test.want {
+			t.Errorf("link to %q = %q, %v, want %q", test.url, buf.String(), err, test.want)
+		}
+	}
+}
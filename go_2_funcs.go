@@ -3,8 +3,13 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 func main() {
@@ -32,6 +37,10 @@ func main() {
 		fmt.Println("I print after main returns and before whatever called main continues.")
 	}() // <-- notice the immediate function call
 
+	// 8 onwards run before the db example below, it stops the program
+	// with log.Fatal when there's no postgres to connect to.
+	runLaterSections()
+
 	// Real Example: postgres db connection
 	//
 	db, err := sql.Open( // Doesn't actually open the db, just parses out the connection info below
@@ -113,6 +122,8 @@ func main() {
 		//   or the function returns the address of the closured variable,
 		//   extending its life beyond the scope here.
 		//     e.g. return &whizzBang
+		//
+		// See it measured in go_11_closure_bench.go
 		return bar + "bazz" + whizzBang
 	}
 	foo("bang") // call it normally
@@ -143,6 +154,117 @@ func main() {
 	fmt.Println(firstPerson.firstName) // jordan
 }
 
+// runLaterSections is sections 8 and up, the same walkthrough as main, but
+// in its own function so main can run it before the db example in 7.
+func runLaterSections() {
+	// 8. Passing structs to functions, see below.
+	// Go passes EVERYTHING by value, i.e. the function gets a copy.
+	// Python/JS pass objects by reference, so this surprises people.
+	alice := User{Name: "Alice", Password: "Gopher123"}
+	resetPasswordCopy(alice)
+	fmt.Println(alice.Password) // Gopher123, unchanged! only the copy was reset
+
+	resetPassword(&alice)
+	fmt.Println(alice.Password) // changeme, the pointer let us reach the original
+
+	// 9. Slices of structs vs slices of pointers
+	//    []User stores the structs themselves, one after another in memory.
+	//    []*User stores addresses, the Users live elsewhere (and can be shared).
+	//
+	// range hands you a COPY of each item, same as passing it to a function.
+	users := []User{{Name: "Bob"}, {Name: "Cindy"}}
+	for _, u := range users {
+		u.Password = "changeme" // ❌ changes the loop's copy
+	}
+	fmt.Println(users[0].Password == "") // true, nothing saved
+
+	// Fix 1: index into the slice, users[i] IS the item, not a copy.
+	for i := range users {
+		users[i].Password = "changeme" // ✅
+	}
+	fmt.Println(users[0].Password) // changeme
+
+	// Fix 2: a slice of pointers, the copy is a copy of the address.
+	userPointers := []*User{{Name: "Doris"}, {Name: "Evan"}}
+	for _, u := range userPointers {
+		u.Password = "changeme" // ✅ same User the slice points at
+	}
+	fmt.Println(userPointers[1].Password) // changeme
+
+	// 10. Stack vs heap (escape analysis), see below.
+	valuePoint := newPointValue()
+	pointerPoint := newPointPointer()
+	nextX := pointCounter()
+	fmt.Println(valuePoint.x, pointerPoint.x, nextX(), nextX()) // 1 1 1 2
+
+	// 11. Defer inside a loop, see below.
+	// Like 7. said, defer conn.Close() right after opening. But in a loop...
+	fmt.Println(deferInLoop(5))       // 5 ❌ all 5 were open at once before any closed
+	fmt.Println(deferPerIteration(5)) // 1 ✅ never more than 1 open
+
+	// 12. Multiple return values, see below.
+	// Like python returning a tuple, and unpacking it: port, err = parse_port("8080")
+	port, err := parsePort("8080")
+	if err != nil {
+		fmt.Println("bad port:", err)
+	}
+	fmt.Println(port) // 8080
+
+	// Handle the error, the normal case.
+	if _, err := parsePort("99999"); err != nil {
+		fmt.Println(err) // port 99999 out of range
+	}
+
+	// Ignore the error with _, ONLY when the zero value is an acceptable answer.
+	// Here a bad port quietly becomes 0, which is fine for "maybe log the port".
+	quietPort, _ := parsePort("not a port")
+	fmt.Println(quietPort) // 0
+
+	// The "comma ok" form, for "might not be there" (not an error, just missing).
+	if age, ok := lookupAge("Alice"); ok {
+		fmt.Println(age) // 33
+	}
+	_, ok := lookupAge("Zed")
+	fmt.Println(ok) // false
+
+	// Unlike python, you must take ALL the values or none:
+	//   port := parsePort("8080")  <-- compile error, 2 values returned
+	parsePort("8080") // allowed, throws both away (go vet and linters may warn, so usually _ = or handle it)
+
+	// 13. Dispatch tables, see below.
+	for _, cmd := range []string{"upper", "reverse", "shout", "dance"} {
+		result, err := dispatch(cmd, "gopher")
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(result)
+	}
+	// GOPHER
+	// rehpog
+	// GOPHER!!!
+	// unknown command "dance", try one of [reverse shout upper]
+
+	// 14. State machines with functions returning functions, see below.
+	fmt.Printf("%q\n", lexFields("one,two,three,four ")) // ["one" "two" "three" "four"], the string from go_1_intro.go
+	fmt.Printf("%q\n", lexFields("one,,three"))          // ["one" "" "three"]
+	fmt.Printf("%q\n", lexFields("one,two,"))            // ["one" "two" ""], the trailing comma means one more empty field
+	fmt.Printf("%q\n", lexFields(""))                    // [""], same as strings.Split("", ",")
+
+	// 15. Closures that remember, see below.
+	countA := makeCounter()
+	countB := makeCounter() // a second, separate count
+
+	fmt.Println(countA(), countA(), countA()) // 1 2 3
+	fmt.Println(countB())                     // 1, not 4, B has its own count
+	fmt.Println(countA())                     // 4, A carried on where it was
+
+	total := makeAccumulator()
+	total(10)
+	total(5)
+	fmt.Println(total(-3)) // 12
+}
+
 // 5. Value receivers on a struct (think of like a class)
 //    This is Go's equivalent of an immutable class, which includes:
 //      a. define the struct and its values (like class instance vars)
@@ -181,3 +303,281 @@ type person struct {
 func (p *person) updateMyName(newName string) {
 	p.firstName = newName
 }
+
+// 8. Passing structs to functions
+//    Same rules as receivers above, a receiver is just a special first param.
+//
+//    Taking a User copies every field into the function. Changes stay
+//    in the function and are thrown away when it returns.
+//    This is the exact same bug as SenderA in go_4_structs_interfaces.go,
+//    its value receiver bumps MessageCount on a copy.
+//
+//    Taking a *User copies just the address (8 bytes), and changes
+//    through it land on the caller's struct.
+//
+//    Rule of thumb: take a *T if you need to change it,
+//    or if the struct is big (copying lots of fields every call adds up).
+type User struct {
+	Name     string
+	Password string
+}
+
+func resetPasswordCopy(u User) {
+	u.Password = "changeme" // ❌ changes the copy only
+}
+
+func resetPassword(u *User) {
+	u.Password = "changeme" // ✅ changes the caller's User, no * needed to reach the field
+}
+
+// 10. Stack vs heap (escape analysis)
+//    Python and JS put (almost) everything on the heap, and a garbage
+//    collector cleans up. Go tries to put things on the stack first, which
+//    is basically free: it's thrown away the moment the function returns.
+//
+//    But if something must outlive its function (we return its address,
+//    or a closure holds onto it), it "escapes" to the heap, and the
+//    garbage collector has to track it.
+//
+//    The compiler will tell you what escapes and why:
+//
+//      go build -gcflags='-m -l' go_2_funcs.go
+//
+//    (-m prints escape decisions, -l turns off inlining so the output
+//    matches the functions as written.) For the funcs below you'll see:
+//
+//      &point{...} escapes to heap     <- newPointPointer
+//      &point{} escapes to heap        <- pointCounter, the closure keeps p alive
+//      func literal escapes to heap    <- the closure itself is returned
+//
+//    and nothing at all for newPointValue, it stays on the stack.
+//
+//    Don't contort your code to avoid the heap, but in a hot loop this is where
+//    surprise allocations come from (see go_11_closure_bench.go).
+type point struct {
+	x, y int
+}
+
+// Returns a copy, the original point dies with this function's stack. No escape.
+func newPointValue() point {
+	return point{x: 1, y: 2}
+}
+
+// Returns an address, so the point must outlive this function. Escapes to heap.
+func newPointPointer() *point {
+	return &point{x: 1, y: 2}
+}
+
+// The returned closure captures p, so p (and the closure) escape to the heap.
+func pointCounter() func() int {
+	p := &point{}
+	return func() int {
+		p.x++
+		return p.x
+	}
+}
+
+// 11. Defer inside a loop
+//    defer runs when the FUNCTION returns, not when the loop iteration ends.
+//    So this classic, per-file or per-query:
+//
+//      for _, name := range names {
+//        rows, _ := db.Query(...)
+//        defer rows.Close()        // ❌ piles up, nothing closes until the loop is done
+//        ...
+//      }
+//
+//    keeps every rows (and its db connection, see the outage story in 7.) open
+//    until the whole loop finishes. 10,000 iterations, 10,000 open connections.
+//
+//    Fix: put the body in its own function so defer fires every iteration,
+//    or call Close() yourself at the end of the iteration.
+//
+//    The funcs below open fake resources and report the most open at once.
+type resource struct {
+	openCount *int
+}
+
+func openResource(openCount *int) *resource {
+	*openCount++
+	return &resource{openCount: openCount}
+}
+
+func (r *resource) Close() error {
+	*r.openCount--
+	return nil
+}
+
+// ❌ Every Close waits until deferInLoop returns.
+func deferInLoop(n int) (mostOpen int) {
+	openCount := 0
+	for i := 0; i < n; i++ {
+		r := openResource(&openCount)
+		defer r.Close()
+
+		mostOpen = max(mostOpen, openCount)
+	}
+	return mostOpen
+}
+
+// ✅ The IIFE (see 4.) gives each iteration its own function, so its defer runs right away.
+func deferPerIteration(n int) (mostOpen int) {
+	openCount := 0
+	for i := 0; i < n; i++ {
+		func() {
+			r := openResource(&openCount)
+			defer r.Close() // runs at the end of this func, i.e. this iteration
+
+			mostOpen = max(mostOpen, openCount)
+		}()
+	}
+	return mostOpen
+}
+
+// 12. Multiple return values
+//    Go functions can return several values, and by convention:
+//      (value, error)  something can go wrong, error is ALWAYS last, nil means ok
+//      (value, bool)   "comma ok", the thing might not exist, like reading a map
+//
+//    _ (the blank identifier) throws away a value you don't need.
+//    Throwing away an error is like python's "except: pass", be sure you mean it.
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err // return the zero value alongside the error
+	}
+	if port < 1 || port > 65535 {
+		return 0, errors.New("port " + s + " out of range")
+	}
+	return port, nil
+}
+
+func lookupAge(name string) (int, bool) {
+	nameToAge := map[string]int{"Alice": 33, "Bob": 42}
+	age, ok := nameToAge[name] // maps use the same comma ok form
+	return age, ok
+}
+
+// 13. Dispatch tables
+//    Functions are values (3. above), so they can go in a map like anything else.
+//    That's a "dispatch table", python's {"upper": str.upper, ...}[cmd](arg).
+//
+//    The same thing as a switch:
+//
+//      switch cmd {
+//      case "upper":
+//        return strings.ToUpper(arg), nil
+//      case "reverse":
+//        ...
+//      default:
+//        return "", fmt.Errorf("unknown command %q", cmd)
+//      }
+//
+//    A switch is fine for a few fixed cases, and the compiler can see every branch.
+//    A map wins when commands get added at runtime (plugins, registering
+//    handlers, like http.HandleFunc does), or when you want to list them.
+var commands = map[string]func(string) string{
+	"upper":   strings.ToUpper, // an existing function, no () so we store it, not call it
+	"reverse": reverseString,
+	"shout": func(s string) string { // or a func literal right here
+		return strings.ToUpper(s) + "!!!"
+	},
+}
+
+// dispatch looks cmd up in commands and runs it on arg.
+func dispatch(cmd, arg string) (string, error) {
+	fn, ok := commands[cmd] // comma ok, same as 12.
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try one of %v", cmd, slices.Sorted(maps.Keys(commands)))
+	}
+	return fn(arg), nil
+}
+
+func reverseString(s string) string {
+	runes := []rune(s) // runes, not bytes, see go_19_runes.go
+	slices.Reverse(runes)
+	return string(runes)
+}
+
+// 14. State machines with functions returning functions
+//    Rob Pike's trick from the text/template lexer (the one go_25_templates.go uses,
+//    see src/text/template/parse/lex.go in the Go source). Each state is a function
+//    that does its bit, then RETURNS the next state. No enum of states, no big switch,
+//    the loop is just  for state != nil { state = state() }
+//
+//    A type can refer to itself, a stateFn returns a stateFn.
+//    Go's lexer passes the lexer in, func(*lexer) stateFn. Ours are methods,
+//    so they already have l, and the type is just func() stateFn.
+type stateFn func() stateFn
+
+// fieldLexer splits "one,two,three" into fields, like strings.Split(s, ",")
+// but trimming spaces. Two states:
+//
+//      lexField  --(comma)-->  lexComma
+//         ^                       |
+//         +-----------------------+
+//         |
+//       (end of input) --> nil, done
+type fieldLexer struct {
+	input  string
+	pos    int // where we're reading
+	fields []string
+}
+
+func lexFields(input string) []string {
+	l := &fieldLexer{input: input}
+	for state := l.lexField; state != nil; { // l.lexField is a "method value", a func with l built in
+		state = state()
+	}
+	return l.fields
+}
+
+// lexField reads up to the next comma (or the end), and keeps it as a field.
+func (l *fieldLexer) lexField() stateFn {
+	start := l.pos
+	for l.pos < len(l.input) && l.input[l.pos] != ',' {
+		l.pos++
+	}
+	l.fields = append(l.fields, strings.TrimSpace(l.input[start:l.pos]))
+
+	if l.pos == len(l.input) {
+		return nil // ran out of input, stop
+	}
+	return l.lexComma
+}
+
+// lexComma steps over the comma. There's always a field after a comma, maybe an empty one.
+func (l *fieldLexer) lexComma() stateFn {
+	l.pos++
+	return l.lexField
+}
+
+// 15. Closures that remember
+//    3. above warned about closures capturing variables. Here it's on purpose.
+//    Each call to makeCounter makes a NEW count variable, and the returned
+//    func holds onto it. count can't live on makeCounter's stack, it has to
+//    outlive the call, so it escapes to the heap (10. above).
+//
+//    It lives exactly as long as someone holds the returned func. Drop
+//    the func and the garbage collector takes count too. That's the "leak"
+//    from 3.: not a bug in Go, just a closure kept around longer than you meant,
+//    keeping everything it captured alive with it.
+//
+//    python needs "nonlocal count" for this, JS works the same as Go.
+//    It's a tiny object with one private field and one method, no struct needed.
+func makeCounter() func() int {
+	count := 0
+	return func() int {
+		count++ // the outer count, not a copy
+		return count
+	}
+}
+
+// makeAccumulator keeps a running total, each call adds n and returns the sum so far.
+func makeAccumulator() func(int) int {
+	sum := 0
+	return func(n int) int {
+		sum += n
+		return sum
+	}
+}
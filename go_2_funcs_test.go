@@ -0,0 +1,276 @@
+package main
+
+//	go test go_2_funcs.go go_2_funcs_test.go
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPassingStructs(t *testing.T) {
+	alice := User{Name: "Alice", Password: "Gopher123"}
+
+	resetPasswordCopy(alice)
+	if alice.Password != "Gopher123" {
+		t.Errorf("after resetPasswordCopy: Password = %q, want it unchanged, only the copy was reset", alice.Password)
+	}
+
+	resetPassword(&alice)
+	if alice.Password != "changeme" {
+		t.Errorf("after resetPassword: Password = %q, want changeme", alice.Password)
+	}
+}
+
+func TestReceivers(t *testing.T) {
+	original := user{firstName: "sam"}
+	updated := original.updateNameAndCopy("alex")
+	if original.firstName != "sam" || updated.firstName != "alex" {
+		t.Errorf("original %q, updated %q, want sam and alex", original.firstName, updated.firstName)
+	}
+
+	p := person{}
+	p.updateMyName("jordan")
+	if p.firstName != "jordan" {
+		t.Errorf("pointer receiver: firstName = %q, want jordan", p.firstName)
+	}
+}
+
+func TestSlicesOfStructsVsPointers(t *testing.T) {
+	t.Run("range over values changes copies", func(t *testing.T) {
+		users := []User{{Name: "Bob"}, {Name: "Cindy"}}
+		for _, u := range users {
+			u.Password = "changeme"
+		}
+		for _, u := range users {
+			if u.Password != "" {
+				t.Errorf("%s: Password = %q, want the loop's change thrown away", u.Name, u.Password)
+			}
+		}
+	})
+
+	t.Run("indexing changes the real items", func(t *testing.T) {
+		users := []User{{Name: "Bob"}, {Name: "Cindy"}}
+		for i := range users {
+			users[i].Password = "changeme"
+		}
+		for _, u := range users {
+			if u.Password != "changeme" {
+				t.Errorf("%s: Password = %q, want changeme", u.Name, u.Password)
+			}
+		}
+	})
+
+	t.Run("range over pointers changes the real items", func(t *testing.T) {
+		doris := &User{Name: "Doris"}
+		userPointers := []*User{doris, {Name: "Evan"}}
+		for _, u := range userPointers {
+			u.Password = "changeme"
+		}
+		for _, u := range userPointers {
+			if u.Password != "changeme" {
+				t.Errorf("%s: Password = %q, want changeme", u.Name, u.Password)
+			}
+		}
+		if doris.Password != "changeme" {
+			t.Error("doris is the same User the slice points at, she should see the change")
+		}
+	})
+}
+
+// The escape analysis lesson is in `go build -gcflags='-m -l' go_2_funcs.go`,
+// this just keeps the three funcs working.
+func TestEscapeAnalysisFuncs(t *testing.T) {
+	if got := newPointValue(); got != (point{x: 1, y: 2}) {
+		t.Errorf("newPointValue = %v", got)
+	}
+	first, second := newPointPointer(), newPointPointer()
+	if *first != (point{x: 1, y: 2}) || first == second {
+		t.Errorf("newPointPointer = %v, %v, want two separate points", first, second)
+	}
+
+	next := pointCounter()
+	if a, b, c := next(), next(), next(); a != 1 || b != 2 || c != 3 {
+		t.Errorf("pointCounter = %d %d %d, want 1 2 3", a, b, c)
+	}
+}
+
+func TestDeferInLoop(t *testing.T) {
+	for _, n := range []int{1, 5, 100} {
+		if got := deferInLoop(n); got != n {
+			t.Errorf("deferInLoop(%d): %d open at once, want all %d, nothing closes until it returns", n, got, n)
+		}
+		if got := deferPerIteration(n); got != 1 {
+			t.Errorf("deferPerIteration(%d): %d open at once, want 1", n, got)
+		}
+	}
+	if deferInLoop(0) != 0 || deferPerIteration(0) != 0 {
+		t.Error("no iterations should open nothing")
+	}
+
+	// Either way they're all closed by the end, the bug is only HOW LONG they stay open.
+	openCount := 0
+	for i := 0; i < 3; i++ {
+		r := openResource(&openCount)
+		r.Close()
+	}
+	if openCount != 0 {
+		t.Errorf("openCount = %d after closing everything, want 0", openCount)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr string
+	}{
+		{"8080", 8080, ""},
+		{"1", 1, ""},
+		{"65535", 65535, ""},
+		{"0", 0, "port 0 out of range"},
+		{"99999", 0, "port 99999 out of range"},
+		{"-1", 0, "port -1 out of range"},
+		{"not a port", 0, "invalid syntax"},
+		{"", 0, "invalid syntax"},
+	}
+	for _, test := range tests {
+		port, err := parsePort(test.input)
+		if test.wantErr == "" {
+			if err != nil || port != test.want {
+				t.Errorf("parsePort(%q) = %d, %v, want %d", test.input, port, err, test.want)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("parsePort(%q) err = %v, want %q", test.input, err, test.wantErr)
+		}
+		// Ignoring the error with _ is only OK because of this, a failed parse is always 0.
+		if port != 0 {
+			t.Errorf("parsePort(%q) = %d alongside an error, want the zero value", test.input, port)
+		}
+	}
+
+	// The error from Atoi comes back as is, so it's still a *strconv.NumError.
+	_, err := parsePort("abc")
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Num != "abc" {
+		t.Errorf("parsePort(abc) err = %#v, want a *strconv.NumError", err)
+	}
+}
+
+func TestParsePortIgnoringTheError(t *testing.T) {
+	quietPort, _ := parsePort("not a port")
+	if quietPort != 0 {
+		t.Errorf("quietPort = %d, want 0", quietPort)
+	}
+	goodPort, _ := parsePort("443")
+	if goodPort != 443 {
+		t.Errorf("goodPort = %d, want 443, ignoring the error doesn't change a good answer", goodPort)
+	}
+}
+
+func TestLookupAge(t *testing.T) {
+	if age, ok := lookupAge("Alice"); age != 33 || !ok {
+		t.Errorf("lookupAge(Alice) = %d, %v, want 33, true", age, ok)
+	}
+	if age, ok := lookupAge("Zed"); age != 0 || ok {
+		t.Errorf("lookupAge(Zed) = %d, %v, want 0, false", age, ok)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct{ cmd, arg, want string }{
+		{"upper", "gopher", "GOPHER"},
+		{"reverse", "gopher", "rehpog"},
+		{"reverse", "🍎🤓", "🤓🍎"}, // by runes, the emoji stay whole
+		{"shout", "gopher", "GOPHER!!!"},
+		{"upper", "", ""},
+	}
+	for _, test := range tests {
+		got, err := dispatch(test.cmd, test.arg)
+		if err != nil || got != test.want {
+			t.Errorf("dispatch(%q, %q) = %q, %v, want %q", test.cmd, test.arg, got, err, test.want)
+		}
+	}
+
+	for _, cmd := range []string{"dance", "", "UPPER"} { // map keys are case sensitive
+		got, err := dispatch(cmd, "gopher")
+		if err == nil || got != "" {
+			t.Errorf("dispatch(%q) = %q, %v, want an error", cmd, got, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), "try one of [reverse shout upper]") {
+			t.Errorf("dispatch(%q) err = %q, want it to list the commands", cmd, err)
+		}
+	}
+}
+
+func TestDispatchTableCanGrow(t *testing.T) {
+	commands["double"] = func(s string) string { return s + s }
+	t.Cleanup(func() { delete(commands, "double") })
+
+	if got, err := dispatch("double", "go"); err != nil || got != "gogo" {
+		t.Errorf("dispatch(double) = %q, %v, want gogo", got, err)
+	}
+}
+
+func TestLexFields(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"one,two,three,four ", []string{"one", "two", "three", "four"}},
+		{"one, two ,  three", []string{"one", "two", "three"}},
+		{"one,,three", []string{"one", "", "three"}},
+		{"one,two,", []string{"one", "two", ""}}, // trailing comma, one more empty field
+		{",one", []string{"", "one"}},
+		{",", []string{"", ""}},
+		{"one", []string{"one"}},
+		{"", []string{""}},
+	}
+	for _, test := range tests {
+		got := lexFields(test.input)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("lexFields(%q) = %q, want %q", test.input, got, test.want)
+		}
+		// Same answer as the stdlib, apart from the trimming.
+		var split []string
+		for _, field := range strings.Split(test.input, ",") {
+			split = append(split, strings.TrimSpace(field))
+		}
+		if !slices.Equal(got, split) {
+			t.Errorf("lexFields(%q) = %q, strings.Split gives %q", test.input, got, split)
+		}
+	}
+}
+
+func TestCountersDontShareState(t *testing.T) {
+	countA, countB := makeCounter(), makeCounter()
+
+	for want := 1; want <= 3; want++ {
+		if got := countA(); got != want {
+			t.Fatalf("countA() = %d, want %d", got, want)
+		}
+	}
+	if got := countB(); got != 1 {
+		t.Errorf("countB() = %d after 3 countA() calls, want 1, B has its own count", got)
+	}
+	if got := countA(); got != 4 {
+		t.Errorf("countA() = %d, want 4, carrying on where it was", got)
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	total, other := makeAccumulator(), makeAccumulator()
+	for _, step := range []struct{ add, want int }{{10, 10}, {5, 15}, {-3, 12}, {0, 12}} {
+		if got := total(step.add); got != step.want {
+			t.Errorf("total(%d) = %d, want %d", step.add, got, step.want)
+		}
+	}
+	if got := other(1); got != 1 {
+		t.Errorf("other(1) = %d, want 1, it doesn't share total's sum", got)
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -50,6 +54,18 @@ func main() {
 		fmt.Println("Finished waiting for that goroutine")
 	}
 
+	// How long did that wait take? Use measure (see below) to time any func.
+	waitTook := measure(func() {
+		go func() { boolChannel <- true }()
+		<-boolChannel
+	})
+	fmt.Printf("goroutine round trip took %v\n", waitTook)
+
+	// Sleeping 10ms will always measure as >= 10ms, never negative,
+	// even if the system clock jumps backwards while sleeping.
+	sleepTook := measure(func() { time.Sleep(10 * time.Millisecond) })
+	fmt.Println(sleepTook >= 0, sleepTook >= 10*time.Millisecond) // true true
+
 	// A more common way to get messages from a channel is use a for loop on it.
 	// The for loop will block in its thread until receiving a message on the channel
 	// or a channel close event.
@@ -145,6 +161,130 @@ func main() {
 	// DO NOT CLOSE CHANNELS UNLESS YOU REALLY (REALLY) KNOW WHAT YOU'RE DOING
 	// close(messageChan) <-- will likely cause a panic
 
+	// But say you DO own the channel, and you're done sending. Will closing it
+	// throw away the messages still sitting in the buffer?
+	//
+	// No! A closed channel keeps handing out its buffered messages, and
+	// only reports ok == false once the buffer is empty. So "ok == false"
+	// already means "drained".
+	//
+	// Where messages really get lost is main returning (killing the program) before
+	// the consumer finishes the buffer. So the consumer has to tell us when it's done.
+	jobsChan := make(chan string, 5)
+	drainedChan := make(chan int, 1) // the consumer sends how many jobs it processed
+	go drainingConsumer(jobsChan, drainedChan)
+
+	for _, job := range []string{"a", "b", "c", "d", "e"} {
+		jobsChan <- job // buffer of 5, fits, won't block
+	}
+	close(jobsChan) // we're the only sender, so we're allowed to close it
+
+	processed := <-drainedChan                    // WAIT for the consumer, don't just return
+	fmt.Println(processed, "of 5 jobs processed") // 5 of 5 jobs processed
+
+	// The guess-and-check buffer advice above, but measured instead of "see how fast".
+	// A meteredChan counts every time the producer had to wait for space
+	// (buffer too small) and every time the consumer had to wait for a message (nothing to do).
+	// Same bursty producer and same consumer, only the buffer changes.
+	tiny := runBurstyPipeline(1)
+	roomy := runBurstyPipeline(100)
+	fmt.Println(tiny.ProducerBlocks() > roomy.ProducerBlocks()) // true, bursts of 10 don't fit in 1
+	fmt.Println(roomy.ProducerBlocks())                         // 0, a whole burst fits, the producer never waits
+	fmt.Println(roomy.ConsumerWaits() > 0)                      // true, between bursts the consumer runs dry
+	// Producer blocking a lot: grow the buffer (or speed up the consumer).
+	// Consumer waiting a lot: the buffer is plenty, the producer is the slow part.
+
+	// One select can wait on many different things at once. Here a worker
+	// handles work, says "alive" on a timer, and quits when told to, all in one loop.
+	workChan := make(chan string, 10)
+	stopChan := make(chan struct{})
+	heartbeatsChan := make(chan int, 1)
+	go heartbeatWorker(workChan, stopChan, 100*time.Millisecond, heartbeatsChan)
+
+	for _, work := range []string{"slow 1", "slow 2", "slow 3"} {
+		workChan <- work
+		time.Sleep(150 * time.Millisecond) // slow producer, so the heartbeat has time to fire
+	}
+	close(stopChan) // closing a channel wakes up EVERY goroutine reading it, a "broadcast"
+	heartbeats := <-heartbeatsChan
+	fmt.Println(heartbeats > 0) // true, the worker said "alive" while working
+
+	// Some messages matter more. When both channels have something waiting,
+	// select picks one AT RANDOM, there's no "check top to bottom" like a switch.
+	// So this gives no priority at all, a 2fa code can sit behind 1,000 newsletters:
+	//
+	//	select {
+	//	case msg := <-highChan:  // ❌ only wins about half the time
+	//	case msg := <-lowChan:
+	//	}
+	//
+	// processByPriority checks high on its own first, see below.
+	highChan := make(chan string, 10)
+	lowChan := make(chan string, 10)
+	for _, newsletter := range []string{"newsletter 1", "newsletter 2", "newsletter 3"} {
+		lowChan <- newsletter
+	}
+	highChan <- "password reset" // sent after the newsletters...
+	highChan <- "2fa code"
+	close(highChan)
+	close(lowChan)
+	fmt.Println(processByPriority(highChan, lowChan))
+	// [password reset 2fa code newsletter 1 newsletter 2 newsletter 3] ...but handled first ✅
+
+	// Goroutines in a loop, the classic gotcha.
+	//
+	// Before Go 1.22, a for loop had ONE v variable, reused every iteration.
+	// Goroutines that captured v usually ran after the loop moved on
+	// (or finished), so they all printed the LAST value: "zow zow zow zow".
+	// The fix everyone memorized was copying it first:
+	//
+	//	for _, v := range values {
+	//	  v := v // fresh copy just for this iteration
+	//	  go func() { fmt.Println(v) }()
+	//	}
+	//
+	// Go 1.22+ gives every iteration its own fresh v, so the bug is gone.
+	// (That depends on the go version in go.mod, old modules keep the old behavior.)
+	loopValues := []string{"pop", "bang", "whack", "zow"}
+	seenChan := make(chan string, len(loopValues))
+	for _, v := range loopValues {
+		go func() {
+			seenChan <- v // each goroutine has its own v
+		}()
+	}
+	seen := map[string]bool{}
+	for range loopValues {
+		seen[<-seenChan] = true // wait for all 4 goroutines
+	}
+	fmt.Println(len(seen)) // 4, every value showed up once (pre 1.22 this was often 1)
+
+	// A panic in a goroutine takes down the WHOLE program, not just that goroutine,
+	// and nothing outside the goroutine can recover it. safely turns it into an error.
+	err := safely(func() error {
+		var prices map[string]int
+		prices["AAPL"] = 200 // panics, nil map write
+		return nil
+	})
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		fmt.Println(panicErr.Value)                                // assignment to entry in nil map
+		fmt.Println(strings.Contains(panicErr.Stack, "main.main")) // true, the stack shows where it blew up
+	}
+	fmt.Println(safely(func() error { return errors.New("plain error") })) // plain error, passed through as is
+
+	// Go is `go fn()` with safely built in, the panic gets logged instead.
+	// Catch what it logs, so we can check it (and wait for it).
+	caught := make(logCatcher, 1)
+	log.SetOutput(caught)
+	Go(func() {
+		var prices map[string]int
+		prices["GOOG"] = 100
+	})
+	logged := <-caught
+	log.SetOutput(os.Stderr)                                                // back to normal, where log writes by default
+	fmt.Println(strings.Contains(logged, "assignment to entry in nil map")) // true
+	fmt.Println("still running")                                            // still running, the program survived
+
 	// So how are channels typically used? Here's an example.
 	// Let's spam stock market data, and convert it to emojis.
 	//
@@ -176,10 +316,11 @@ func main() {
 	// Fire up 4 workers listening for data on the ticker channel.
 	// When they get a symbol, they'll convert it.
 	maxEmojis := 100000
-	go stockEmojiWorker(stockTickerChan, workerDoneChan, "AAPL", "🍎", maxEmojis)
-	go stockEmojiWorker(stockTickerChan, workerDoneChan, "GOOG", "🤓", maxEmojis)
-	go stockEmojiWorker(stockTickerChan, workerDoneChan, "FB", "🤢", maxEmojis)
-	go stockEmojiWorker(stockTickerChan, workerDoneChan, "AMZN", "📦", maxEmojis)
+	// Go instead of go, so one bad worker can't crash the rest.
+	Go(func() { stockEmojiWorker(stockTickerChan, workerDoneChan, "AAPL", "🍎", maxEmojis) })
+	Go(func() { stockEmojiWorker(stockTickerChan, workerDoneChan, "GOOG", "🤓", maxEmojis) })
+	Go(func() { stockEmojiWorker(stockTickerChan, workerDoneChan, "FB", "🤢", maxEmojis) })
+	Go(func() { stockEmojiWorker(stockTickerChan, workerDoneChan, "AMZN", "📦", maxEmojis) })
 	//
 	// Fire up 2 spammers. As soon as these start running, data will
 	// flow through the channel to the workers. Each worker arbitrarily
@@ -228,6 +369,80 @@ func printFoo() {
 	fmt.Println("foo")
 }
 
+// I time how long whatever func you give me takes to run.
+//
+// time.Now() secretly carries two clocks:
+//   - the wall clock (what your watch says, used for printing and formatting)
+//   - a monotonic clock (only ever ticks forward, used for measuring)
+//
+// time.Since(start) and start.Sub(other) use the monotonic reading, so
+// if NTP, daylight savings, or someone fat-fingering `date` moves the
+// wall clock during fn, the duration is still correct.
+//
+// Compare with the Python-ish way of doing it:
+//
+//	start := time.Now().Unix()
+//	fn()
+//	took := time.Now().Unix() - start // ❌ wall clock only, can be negative after a clock adjustment
+//
+// Unix() strips the monotonic reading (and rounds to seconds), so only
+// use it for timestamps, never for measuring durations.
+func measure(fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	return time.Since(start)
+}
+
+// I process every job on the channel until it's closed AND empty, then report how many I did.
+//
+// Notice the receive-only (<-chan) and send-only (chan<-) types, the
+// compiler stops me from accidentally sending jobs or reading the count.
+func drainingConsumer(jobs <-chan string, done chan<- int) {
+	count := 0
+	for {
+		select {
+		case job, ok := <-jobs:
+			if !ok {
+				// Closed, and every buffered job already came through above.
+				done <- count
+				return
+			}
+
+			fmt.Println("processing job " + job)
+			count++
+		}
+	}
+}
+
+// I do work, say "alive" every interval, and stop when stop is closed.
+// When I stop I report how many heartbeats I sent.
+//
+// Each case in the select is a different event source:
+//   - work:   a job to do
+//   - ticker: time for a heartbeat (a monitoring system would watch for these)
+//   - stop:   time to quit
+//
+// Whichever is ready first wins. If none are ready, the select sleeps
+// (no CPU) until one is. In production interval might be time.Second, the demo uses 100ms.
+func heartbeatWorker(work <-chan string, stop <-chan struct{}, interval time.Duration, heartbeatsDone chan<- int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop() // ALWAYS schedule it to stop later, otherwise mem leak
+
+	heartbeats := 0
+	for {
+		select {
+		case job := <-work:
+			fmt.Println("working on " + job)
+		case <-ticker.C:
+			heartbeats++
+			log.Print("alive")
+		case <-stop:
+			heartbeatsDone <- heartbeats
+			return
+		}
+	}
+}
+
 // I spam whatever channel you give me.
 func stockSymbolSpammer(stockChan chan string) {
 	stockSymbols := []string{"AAPL", "GOOG", "FB", "AMZN"}
@@ -243,6 +458,9 @@ func stockSymbolSpammer(stockChan chan string) {
 
 // I convert whatever stocks you give me to emoji, up to max emojis.
 func stockEmojiWorker(stockChan chan string, doneChan chan bool, ticker, icon string, maxEmojis int) {
+	// Deferred, so it still reports done if we panic, deferred funcs run on the way out either way.
+	// Otherwise a crashed worker would leave main waiting for it until the timeout.
+	defer func() { doneChan <- true }()
 	emojiCount := 0
 
 	// Continuously read from the channel with range, so helpful!
@@ -257,6 +475,162 @@ func stockEmojiWorker(stockChan chan string, doneChan chan bool, ticker, icon st
 			break
 		}
 	}
+}
 
-	doneChan <- true
+// processByPriority handles everything from high and low until both are
+// closed, always emptying high before taking anything from low.
+// Returns the order they were handled in.
+//
+// Limit: a steady stream on high means low never gets a turn ("starvation"),
+// if that matters, let one low through every N highs.
+func processByPriority(high, low <-chan string) []string {
+	var order []string
+	handle := func(message string, ok bool, from *<-chan string) {
+		if !ok {
+			*from = nil // closed, a nil channel is never ready, so select skips it from now on
+			return
+		}
+		order = append(order, message)
+	}
+
+	for high != nil || low != nil {
+		// High only. default makes it "anything there right now? no? move on".
+		select {
+		case message, ok := <-high:
+			handle(message, ok, &high)
+			continue // back to the top, check high again
+		default:
+		}
+
+		// Nothing high waiting, so wait for whichever comes first.
+		// A high one arriving while we wait can still win here.
+		select {
+		case message, ok := <-high:
+			handle(message, ok, &high)
+		case message, ok := <-low:
+			handle(message, ok, &low)
+		}
+	}
+	return order
+}
+
+// meteredChan is a channel that counts how often each side had to wait.
+type meteredChan struct {
+	ch             chan string
+	producerBlocks atomic.Int64
+	consumerWaits  atomic.Int64
+}
+
+func newMeteredChan(buffer int) *meteredChan {
+	return &meteredChan{ch: make(chan string, buffer)}
+}
+
+// Send tries without waiting first, if that doesn't work the buffer is full,
+// count it, then wait like a normal send.
+func (m *meteredChan) Send(message string) {
+	select {
+	case m.ch <- message:
+		return
+	default:
+	}
+	m.producerBlocks.Add(1)
+	m.ch <- message
+}
+
+// Receive is the same trick, an empty buffer means the consumer has to wait.
+func (m *meteredChan) Receive() (string, bool) {
+	select {
+	case message, ok := <-m.ch:
+		return message, ok
+	default:
+	}
+	m.consumerWaits.Add(1)
+	message, ok := <-m.ch
+	return message, ok
+}
+
+func (m *meteredChan) Close()                { close(m.ch) }
+func (m *meteredChan) ProducerBlocks() int64 { return m.producerBlocks.Load() }
+func (m *meteredChan) ConsumerWaits() int64  { return m.consumerWaits.Load() }
+
+// runBurstyPipeline sends 5 bursts of 10 messages, with a pause between bursts,
+// to a consumer that takes a little while per message. Returns the channel to read its counts.
+func runBurstyPipeline(buffer int) *meteredChan {
+	pipe := newMeteredChan(buffer)
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for {
+			if _, ok := pipe.Receive(); !ok {
+				return
+			}
+			time.Sleep(50 * time.Microsecond) // "processing"
+		}
+	}()
+
+	for burst := 0; burst < 5; burst++ {
+		for i := 0; i < 10; i++ {
+			pipe.Send(fmt.Sprint("tick ", burst, i))
+		}
+		time.Sleep(20 * time.Millisecond) // quiet between bursts, long enough for the consumer to catch up
+	}
+	pipe.Close()
+	<-consumed
+	return pipe
+}
+
+// Go runs fn in a new goroutine, logging a panic instead of crashing the program.
+//
+// Use it for fire and forget goroutines, where nobody is around to get an error.
+// If someone is waiting for the result, recover the panic with safely and send them the error instead.
+func Go(fn func()) {
+	go func() {
+		err := safely(func() error {
+			fn()
+			return nil
+		})
+		if err != nil {
+			log.Println("goroutine crashed:", err)
+		}
+	}()
+}
+
+// logCatcher is an io.Writer that sends everything written to it down the channel.
+// log.SetOutput(caught) sends each log line there instead of the terminal.
+type logCatcher chan string
+
+func (c logCatcher) Write(p []byte) (int, error) {
+	c <- string(p)
+	return len(p), nil
+}
+
+// PanicError is a recovered panic, with the stack trace from where it happened.
+// A struct instead of a plain fmt.Errorf, so error reporting (logs, Sentry...)
+// can get the parts separately with errors.As.
+type PanicError struct {
+	Value any    // whatever was passed to panic()
+	Stack string // from debug.Stack()
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", e.Value, e.Stack)
+}
+
+// safely runs fn, turning a panic into a *PanicError. Like python's
+// try: fn() except Exception as e: traceback.format_exc()
+//
+// recover only works in a deferred func, in the same goroutine as the panic.
+// So every goroutine that might panic needs its own safely (or defer/recover),
+// go_9_http.go's recoverMiddleware is the same trick for http handlers.
+//
+// err is a named result, so the deferred func can set it after the panic.
+func safely(fn func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			// debug.Stack() here, inside the deferred func, still shows the panicking
+			// code, the stack hasn't been unwound yet.
+			err = &PanicError{Value: recovered, Stack: string(debug.Stack())}
+		}
+	}()
+	return fn()
 }
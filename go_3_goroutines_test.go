@@ -0,0 +1,137 @@
+package main
+
+//	go test go_3_goroutines.go go_3_goroutines_test.go
+//
+// Add -race to have the race detector watch every goroutine these start.
+
+import (
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasure(t *testing.T) {
+	took := measure(func() { time.Sleep(5 * time.Millisecond) })
+	if took < 5*time.Millisecond {
+		t.Errorf("measure = %v, want at least the 5ms it slept", took)
+	}
+	if took := measure(func() {}); took < 0 {
+		t.Errorf("measure(nothing) = %v, a monotonic duration is never negative", took)
+	}
+}
+
+// Jobs still buffered when the channel is closed must be processed, not lost.
+func TestDrainingConsumerProcessesEverythingBuffered(t *testing.T) {
+	jobs := make(chan string, 5)
+	for _, job := range []string{"a", "b", "c", "d", "e"} {
+		jobs <- job
+	}
+	close(jobs) // closed while full
+
+	done := make(chan int)
+	go drainingConsumer(jobs, done)
+	if count := <-done; count != 5 {
+		t.Errorf("processed %d jobs, want all 5", count)
+	}
+}
+
+func TestHeartbeatWorkerBeatsWhileWorking(t *testing.T) {
+	work := make(chan string)
+	stop := make(chan struct{})
+	heartbeats := make(chan int, 1)
+	go heartbeatWorker(work, stop, time.Millisecond, heartbeats)
+
+	work <- "slow job"
+	time.Sleep(20 * time.Millisecond) // "processing", 20 ticks worth
+	close(stop)
+	if got := <-heartbeats; got < 1 {
+		t.Errorf("%d heartbeats, want at least 1", got)
+	}
+}
+
+func TestProcessByPriority(t *testing.T) {
+	high := make(chan string, 10)
+	low := make(chan string, 10)
+	for _, message := range []string{"low 1", "low 2", "low 3"} {
+		low <- message
+	}
+	for _, message := range []string{"high 1", "high 2"} { // sent last
+		high <- message
+	}
+	close(high)
+	close(low)
+
+	got := processByPriority(high, low)
+	want := []string{"high 1", "high 2", "low 1", "low 2", "low 3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("processByPriority = %q, want %q", got, want)
+	}
+}
+
+func TestGoroutinesInALoopGetTheirOwnValue(t *testing.T) {
+	values := []string{"pop", "bang", "whack", "zow"}
+	seenChan := make(chan string, len(values))
+	for _, v := range values {
+		go func() { seenChan <- v }()
+	}
+
+	seen := map[string]int{}
+	for range values {
+		seen[<-seenChan]++
+	}
+	for _, v := range values {
+		if seen[v] != 1 {
+			t.Errorf("%q seen %d times, want 1 (all seen: %v)", v, seen[v], seen)
+		}
+	}
+}
+
+func TestSafely(t *testing.T) {
+	err := safely(func() error { panic("boom") })
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("err = %v, want a *PanicError", err)
+	}
+	if panicErr.Value != "boom" {
+		t.Errorf("Value = %v, want boom", panicErr.Value)
+	}
+	if !strings.Contains(panicErr.Stack, "TestSafely") {
+		t.Errorf("the stack should show where the panic happened, got:\n%s", panicErr.Stack)
+	}
+
+	plain := errors.New("plain error")
+	if err := safely(func() error { return plain }); err != plain {
+		t.Errorf("safely(returns an error) = %v, want %v untouched", err, plain)
+	}
+}
+
+func TestGoSurvivesAPanic(t *testing.T) {
+	caught := make(logCatcher, 1)
+	log.SetOutput(caught)
+	defer log.SetOutput(os.Stderr)
+
+	Go(func() { panic("db driver bug") })
+
+	select {
+	case logged := <-caught:
+		if !strings.Contains(logged, "goroutine crashed") || !strings.Contains(logged, "db driver bug") {
+			t.Errorf("logged %q, want the crash and the panic value", logged)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("nothing was logged")
+	}
+	// Still here, so the panic didn't take the test binary down with it.
+}
+
+func TestSmallBufferBlocksTheProducerMore(t *testing.T) {
+	unbuffered := runBurstyPipeline(0)
+	buffered := runBurstyPipeline(10) // a whole burst fits
+	if unbuffered.ProducerBlocks() <= buffered.ProducerBlocks() {
+		t.Errorf("producer blocked %d times with no buffer, %d with 10, want more with no buffer",
+			unbuffered.ProducerBlocks(), buffered.ProducerBlocks())
+	}
+}
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // SenderA shows an example of how to make a "class" in Go.
@@ -28,7 +37,7 @@ type SenderA struct {
 //		  |
 //		  |
 //		 \/
-func (s SenderA) Send(message string) {
+func (s SenderA) Send(message string) error {
 
 	// Common Bug
 	//   Value receivers use a COPY of fields, not the original.
@@ -44,12 +53,16 @@ func (s SenderA) Send(message string) {
 
 	// ❌ Common Bug
 	s.FirstName = "what will happen????" // nothing, not saved
+
+	return nil
 }
 
 // SenderB shows a similar setup, but with a "pointer receiver".
 type SenderB struct {
 	FirstName    string
 	MessageCount int
+
+	config senderConfig // optional extras, see NewConfigurableSender
 }
 
 // Send this time is a "pointer receiver", like an "instance" class method.
@@ -60,13 +73,17 @@ type SenderB struct {
 //		   | |
 //		   | |
 //		  \/\/
-func (s *SenderB) Send(message string) {
+func (s *SenderB) Send(message string) error {
 	// As a pointer receiver, we can update the internal fields
 	//   on the struct with each call, like MessageCount.
+	if err := s.deliver(message); err != nil {
+		return err // not sent, so not counted
+	}
 	s.MessageCount++ // ✅ update is saved
-	fmt.Printf("Send %d from %s: %s\n", s.MessageCount, s.FirstName, message)
+	s.printf("Send %d from %s: %s%s\n", s.MessageCount, s.FirstName, s.config.prefix, message)
 
-	// no need to return anything, s was modified
+	// no need to return anything but the error, s was modified
+	return nil
 }
 
 // Let's create some for an example.
@@ -167,19 +184,107 @@ func runSenders() {
 // method to use below it. This allows our method to
 // say hey I don't care what you give me as along
 // as I can call the method "Send".
+//
+// Send returns an error, because sending can fail (server down,
+// bad address, timeout...).
+//
+// Python and JS raise/throw exceptions, which fly up the stack until
+// someone catches them, or the program dies. Go has no exceptions.
+// Instead the error is just another return value, ALWAYS the last one:
+//
+//	err := sender.Send("hi")    // python: try: sender.send("hi")
+//	if err != nil {             //         except Exception as err:
+//	  return err                //           raise
+//	}
+//
+//	n, err := strconv.Atoi("42") // value(s) first, error last
+//
+// nil means it worked. It's verbose, but you can see every
+// place something can fail just by reading the code.
 type SenderInterface interface {
-	Send(message string)
+	Send(message string) error
 }
 
 // SendEmail allows us to "overload" it with any
 // sender implementation we want. This is
 // "polymorphism" in Go.
-func SendEmail(sender SenderInterface, message string) {
-	sender.Send(message)
+//
+// ctx comes first, by convention (go_10_context.go). When it's cancelled
+// or times out, SendEmail gives up and returns ctx.Err():
+//   - cancelled before we start? Don't even try.
+//   - a ContextSender (like SlowSender) gets ctx, and stops early itself.
+//   - a plain Send can't be interrupted, it runs to the end.
+func SendEmail(ctx context.Context, sender SenderInterface, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if contextSender, ok := sender.(ContextSender); ok {
+		return contextSender.SendContext(ctx, message)
+	}
+	return sender.Send(message)
+}
+
+// ContextSender is a sender that can stop early when ctx is cancelled.
+//
+// It's an "optional interface": SendEmail still takes any SenderInterface,
+// and checks with a type assertion whether this one can do better. The
+// standard library does the same, io.Copy checks if your Reader is also an io.WriterTo.
+// So every existing sender keeps working, unchanged.
+type ContextSender interface {
+	SendContext(ctx context.Context, message string) error
+}
+
+// SlowSender takes Delay to send, think a slow SMTP server.
+type SlowSender struct {
+	Delay time.Duration
+	Sent  []string
+}
+
+func (s *SlowSender) Send(message string) error {
+	return s.SendContext(context.Background(), message) // Background is never cancelled
+}
+
+// SendContext waits for whichever comes first, the send finishing, or ctx giving up.
+func (s *SlowSender) SendContext(ctx context.Context, message string) error {
+	select {
+	case <-time.After(s.Delay): // pretend this is the network
+		s.Sent = append(s.Sent, message)
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sending %q: %w", message, ctx.Err()) // %w, so errors.Is(err, context.DeadlineExceeded) works
+	}
+}
+
+func runContextSendEmail() {
+	slow := &SlowSender{Delay: 100 * time.Millisecond}
+
+	// Plenty of time.
+	fmt.Println(SendEmail(context.Background(), slow, "no rush")) // <nil>
+
+	// Only 10ms, SendEmail gives up long before the 100ms send would finish.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := SendEmail(ctx, slow, "hurry up")
+	fmt.Println(err)                                      // sending "hurry up": context deadline exceeded
+	fmt.Println(errors.Is(err, context.DeadlineExceeded)) // true
+	fmt.Println(time.Since(start) < 50*time.Millisecond)  // true, didn't wait for the slow sender
+	fmt.Println(slow.Sent)                                // [no rush]
+
+	// Cancelled already, nobody's Send even gets called.
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	fmt.Println(SendEmail(cancelled, senderA, "never sent")) // context canceled
 }
 
 func runSendersInterface() {
-	SendEmail(senderA, "message four")
+	ctx := context.Background()
+
+	// Our demo senders never fail, they always return nil.
+	// Still check it, the compiler won't make you, but your on-call self will thank you.
+	if err := SendEmail(ctx, senderA, "message four"); err != nil {
+		fmt.Println("could not send:", err)
+	}
 
 	// Recall that SenderB is a pointer receiver, so
 	// we need to pass it be reference (it's address)
@@ -188,11 +293,1005 @@ func runSendersInterface() {
 	// Unlike C++ Golang makes resolving the pointer easy.
 	// I.e. with the interface above we don't care how
 	// the underlying memory is implemented either.
-	SendEmail(&senderB, "message four")
+	if err := SendEmail(ctx, &senderB, "message four"); err != nil {
+		fmt.Println("could not send:", err)
+	}
+
+	// A sender that always fails shows the error path.
+	failing := FailingSender{Err: errors.New("smtp server unreachable")}
+	if err := SendEmail(ctx, failing, "message five"); err != nil {
+		fmt.Println("could not send:", err) // could not send: smtp server unreachable
+	}
+}
+
+// FailingSender always fails with Err, handy for checking
+// that callers actually handle the error.
+type FailingSender struct {
+	Err error
+}
+
+func (s FailingSender) Send(message string) error {
+	return s.Err
+}
+
+// FlakySender is a sender we can take down on purpose, to
+// see how the wrappers below behave when sending fails.
+type FlakySender struct {
+	Down bool
+	Sent []string
+}
+
+func (s *FlakySender) Send(message string) error {
+	if s.Down {
+		return errors.New("flaky sender is down")
+	}
+	s.Sent = append(s.Sent, message)
+	return nil
+}
+
+// NopSender does nothing and never fails, a "null object".
+// Use it as the default when someone doesn't want notifications,
+// instead of leaving a SenderInterface nil.
+//
+// Why not just nil? A nil interface has no type inside, so Go doesn't know
+// WHICH Send to call. Calling a method on it panics:
+//
+//	var sender SenderInterface // nil
+//	sender.Send("hi")          // panic: runtime error: invalid memory address or nil pointer dereference
+//
+// Python's None.send() is the same crash (AttributeError). With a NopSender
+// there's no "if sender != nil" needed before every call, it just works.
+type NopSender struct{}
+
+func (NopSender) Send(message string) error { return nil } // no receiver name, we don't use it
+
+func runNopSender() {
+	ctx := context.Background()
+
+	fmt.Println(SendEmail(ctx, NopSender{}, "into the void")) // <nil>, safe
+
+	var sender SenderInterface      // forgot to set it
+	fmt.Println(sendPanics(sender)) // true, it panicked (and sendPanics recovered)
+}
+
+// sendPanics reports whether calling Send panicked, recovering so the program keeps going.
+func sendPanics(sender SenderInterface) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true // a named result can still be changed in a deferred func
+		}
+	}()
+	_ = sender.Send("hello?")
+	return false
+}
+
+// ErrCircuitOpen is returned while the circuit breaker is refusing to send.
+var ErrCircuitOpen = errors.New("circuit open, not sending")
+
+// CircuitBreakerSender wraps another sender, and stops calling it
+// once it looks broken, like the breaker box in your house.
+//
+//	closed     all good, every Send goes through to the inner sender
+//	  |
+//	  | Threshold failures in a row
+//	 \/
+//	open       Send fails right away with ErrCircuitOpen, inner sender is left alone
+//	  |
+//	  | Cooldown goes by
+//	 \/
+//	half-open  let ONE Send through as a trial
+//	             worked? back to closed
+//	             failed? back to open, wait another Cooldown
+//
+// Why bother? If the email server is down, hammering it with every
+// message just slows us down (each waits on a timeout) and makes it
+// harder for the server to come back up.
+//
+// It is a SenderInterface itself, so SendEmail doesn't know the difference.
+type CircuitBreakerSender struct {
+	Inner     SenderInterface
+	Threshold int           // failures in a row before opening
+	Cooldown  time.Duration // how long to stay open before a trial send
+
+	// Now tells the time, time.Now by default.
+	// Swap it for a fake clock to step through cooldowns without sleeping.
+	Now func() time.Time
+
+	mu       sync.Mutex // Send might be called from many goroutines
+	failures int
+	openedAt time.Time // zero value means closed
+	trying   bool      // a half-open trial send is in flight
+}
+
+// NewCircuitBreakerSender wraps inner with a breaker using the real clock.
+func NewCircuitBreakerSender(inner SenderInterface, threshold int, cooldown time.Duration) *CircuitBreakerSender {
+	c := CircuitBreakerSender{Inner: inner, Threshold: threshold, Cooldown: cooldown, Now: time.Now}
+	var _ SenderInterface = &c // Interface checked here
+	return &c
+}
+
+func (c *CircuitBreakerSender) Send(message string) error {
+	// The lock is only held to read and update the state, never while sending.
+	// Holding it across Inner.Send would let one slow send (say a 30s SMTP
+	// timeout) block every other goroutine, even the ones that would fail fast.
+	c.mu.Lock()
+	isOpen := !c.openedAt.IsZero()
+	if isOpen && (c.trying || c.Now().Sub(c.openedAt) < c.Cooldown) {
+		c.mu.Unlock()
+		return ErrCircuitOpen // short circuit, don't even try
+	}
+	// Either closed, or open long enough that we're half-open for a trial.
+	// Only ONE trial at a time, everyone else keeps getting ErrCircuitOpen until it's done.
+	c.trying = isOpen
+	c.mu.Unlock()
+
+	err := c.Inner.Send(message)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if isOpen {
+		c.trying = false
+	}
+	if err != nil {
+		c.failures++
+		if isOpen || c.failures >= c.Threshold {
+			c.openedAt = c.Now() // (re)open, restarts the cooldown
+		}
+		return err
+	}
+
+	// Success resets everything back to closed.
+	c.failures = 0
+	c.openedAt = time.Time{}
+	return nil
+}
+
+func runCircuitBreaker() {
+	ctx := context.Background()
+
+	// A fake clock that we move forward by hand.
+	fakeNow := time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	flaky := &FlakySender{Down: true}
+	breaker := NewCircuitBreakerSender(flaky, 3, time.Minute)
+	breaker.Now = func() time.Time { return fakeNow }
+
+	// closed -> open after 3 failures in a row
+	for i := 0; i < 3; i++ {
+		fmt.Println(SendEmail(ctx, breaker, "are you up?")) // flaky sender is down
+	}
+	fmt.Println(SendEmail(ctx, breaker, "are you up?")) // circuit open, not sending (inner not called)
+
+	// Server comes back, but we're still in the cooldown.
+	flaky.Down = false
+	fakeNow = fakeNow.Add(30 * time.Second)
+	fmt.Println(SendEmail(ctx, breaker, "still open?")) // circuit open, not sending
+
+	// Cooldown over: half-open, trial send works, breaker closes.
+	fakeNow = fakeNow.Add(time.Minute)
+	fmt.Println(SendEmail(ctx, breaker, "trial message"))  // <nil>
+	fmt.Println(SendEmail(ctx, breaker, "back to normal")) // <nil>
+	fmt.Println(flaky.Sent)                                // [trial message back to normal]
+}
+
+// ErrBatchClosed is returned when sending on a closed BatchSender.
+var ErrBatchClosed = errors.New("batch sender closed")
+
+// BatchSender collects messages and passes them to Inner in batches,
+// one Send per batch instead of one per message (think bulk email, or
+// bulk db inserts). A batch goes out when either
+//   - Size messages have piled up, or
+//   - Interval goes by (so a slow trickle of messages still gets sent)
+//
+// whichever happens first.
+//
+// Batches are sent from a goroutine, joined by newlines. Always call
+// Close when done, or the last partial batch is lost (and the goroutine leaks).
+type BatchSender struct {
+	inner    SenderInterface
+	size     int
+	interval time.Duration
+
+	messages chan string
+	done     chan struct{} // closed when the goroutine finishes
+	closeErr error         // result of the last flush, read after done
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewBatchSender starts the batching goroutine, e.g. NewBatchSender(inner, 10, time.Second).
+func NewBatchSender(inner SenderInterface, size int, interval time.Duration) *BatchSender {
+	b := BatchSender{
+		inner:    inner,
+		size:     size,
+		interval: interval,
+		messages: make(chan string, size*2), // roomy buffer, so Send rarely hits the default case
+		done:     make(chan struct{}),
+	}
+	var _ SenderInterface = &b // Interface checked here
+
+	go b.run()
+	return &b
+}
+
+// Send queues message for the next batch.
+func (b *BatchSender) Send(message string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBatchClosed // sending on a closed channel would panic
+	}
+
+	select {
+	case b.messages <- message:
+		return nil
+	default:
+		// ALWAYS INCLUDE default FOR CHANNEL WRITES,
+		// and now that Send returns an error we can tell the caller.
+		return errors.New("batch sender backed up, dropping message: " + message)
+	}
+}
+
+// Close flushes whatever is left and stops the goroutine.
+// It waits for the final batch, and returns its error.
+func (b *BatchSender) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.messages) // this is the one place we close, we own the channel
+	}
+	b.mu.Unlock()
+
+	<-b.done // wait for run to flush and return
+	return b.closeErr
+}
+
+func (b *BatchSender) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop() // ALWAYS schedule it to stop later, otherwise mem leak
+
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := b.inner.Send(strings.Join(batch, "\n"))
+		batch = nil // start a fresh batch either way
+		return err
+	}
+
+	for {
+		select {
+		case message, ok := <-b.messages:
+			if !ok {
+				// Close was called, send the partial batch and stop.
+				b.closeErr = flush()
+				return
+			}
+			batch = append(batch, message)
+			if len(batch) >= b.size {
+				if err := flush(); err != nil {
+					log.Print(err) // no caller to return to, we're in a goroutine
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				log.Print(err)
+			}
+		}
+	}
+}
+
+func runBatchSender() {
+	recorder := &FlakySender{}
+	batcher := NewBatchSender(recorder, 10, time.Second)
+
+	// 10 messages fills a batch, flushes right away.
+	for i := 0; i < 10; i++ {
+		_ = batcher.Send(fmt.Sprintf("bulk %d", i))
+	}
+
+	// 3 messages won't fill a batch, the 1 second ticker flushes them.
+	for i := 0; i < 3; i++ {
+		_ = batcher.Send(fmt.Sprintf("trickle %d", i))
+	}
+	time.Sleep(1500 * time.Millisecond)
+
+	// 2 messages left over get flushed by Close.
+	_ = batcher.Send("last one")
+	_ = batcher.Send("really last one")
+	if err := batcher.Close(); err != nil {
+		fmt.Println("final flush failed:", err)
+	}
+	fmt.Println(batcher.Send("too late")) // batch sender closed
+
+	for _, batch := range recorder.Sent {
+		fmt.Println(len(strings.Split(batch, "\n")), "messages in batch") // 10, then 3, then 2
+	}
+}
+
+// Interface segregation: small interfaces
+//
+// BatchSender has Send AND Close. Tempting to write one big interface:
+//
+//	type BigSender interface {
+//	  Send(message string) error
+//	  Close() error
+//	  Flush() error
+//	  Stats() SenderStats
+//	}
+//
+// But then every sender has to have all four, even FailingSender, and every
+// function taking a BigSender "needs" all four, even one that only ever Sends.
+//
+// Go style: lots of tiny interfaces, usually one method, and combine
+// them when you need more. The standard library is full of this,
+// io.Reader, io.Writer, io.Closer, and io.ReadWriteCloser made from the three.
+// "Accept the narrowest interface you need."
+
+// Closer is anything that can be shut down. It's the same as io.Closer.
+type Closer interface {
+	Close() error
+}
+
+// SendCloser embeds both, a type has to have both methods to count.
+// Embedding interfaces in interfaces is like embedding structs, methods get combined.
+type SendCloser interface {
+	SenderInterface
+	Closer
+}
+
+// sendAll only sends, so it only asks for a SenderInterface.
+// Anything works, senders that can't be closed included.
+func sendAll(sender SenderInterface, messages ...string) error {
+	for _, message := range messages {
+		if err := sender.Send(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendAndClose has to close too, so it asks for more.
+func sendAndClose(sender SendCloser, messages ...string) error {
+	return errors.Join(sendAll(sender, messages...), sender.Close())
+}
+
+func runInterfaceSegregation() {
+	// FlakySender has no Close, fine for sendAll.
+	recorder := &FlakySender{}
+	fmt.Println(sendAll(recorder, "one", "two")) // <nil>
+	fmt.Println(recorder.Sent)                   // [one two]
+	// sendAndClose(recorder, "three") <-- ❌ compile error: *FlakySender does not implement SendCloser (missing method Close)
+
+	// BatchSender has both, it's a SendCloser. It's also still just a SenderInterface.
+	batchRecorder := &FlakySender{}
+	var batcher SendCloser = NewBatchSender(batchRecorder, 10, time.Second)
+	fmt.Println(sendAndClose(batcher, "three", "four")) // <nil>
+	fmt.Println(batchRecorder.Sent)                     // [three\nfour], one batch (prints on two lines)
+}
+
+// FallbackSender tries each sender in order, stopping at the first
+// one that works. Primary email provider down? Try the backup.
+//
+// It's a plain slice in a struct, no constructor needed:
+//
+//	FallbackSender{Senders: []SenderInterface{primary, backup}}
+type FallbackSender struct {
+	Senders []SenderInterface
+}
+
+func (f FallbackSender) Send(message string) error {
+	if len(f.Senders) == 0 {
+		return errors.New("fallback sender has no senders")
+	}
+
+	var errs []error
+	for _, sender := range f.Senders {
+		err := sender.Send(message)
+		if err == nil {
+			return nil // someone got it out, done
+		}
+		errs = append(errs, err)
+	}
+
+	// Everyone failed. Keep every error, one per line with the last
+	// one at the bottom, so errors.Is still finds any of them.
+	return fmt.Errorf("all %d senders failed: %w", len(errs), errors.Join(errs...))
+}
+
+func runFallbackSender() {
+	ctx := context.Background()
+
+	primary := &FlakySender{Down: true}
+	backup := &FlakySender{}
+
+	fallback := FallbackSender{Senders: []SenderInterface{primary, backup}}
+	fmt.Println(SendEmail(ctx, fallback, "important")) // <nil>
+	fmt.Println(primary.Sent, backup.Sent)             // [] [important]
+
+	// Everyone is down.
+	backup.Down = true
+	errSMTP := errors.New("smtp server unreachable")
+	allDown := FallbackSender{Senders: []SenderInterface{primary, backup, FailingSender{Err: errSMTP}}}
+
+	err := SendEmail(ctx, allDown, "important")
+	fmt.Println(err)                     // all 3 senders failed: flaky sender is down (x2) smtp server unreachable
+	fmt.Println(errors.Is(err, errSMTP)) // true
+}
+
+// BroadcastSender sends every message to ALL of its senders at once
+// (email + sms + slack), each in its own goroutine.
+type BroadcastSender struct {
+	Senders []SenderInterface
+}
+
+func (b BroadcastSender) Send(message string) error {
+	// One slot per sender, each goroutine only writes its own index,
+	// so no mutex is needed.
+	errs := make([]error, len(b.Senders))
+
+	var wg sync.WaitGroup
+	for i, sender := range b.Senders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sender.Send(message); err != nil {
+				errs[i] = fmt.Errorf("sender %d: %w", i, err)
+			}
+		}()
+	}
+	wg.Wait() // don't return until every sender finished
+
+	// errors.Join skips the nils, and returns nil if they're all nil.
+	return errors.Join(errs...)
+}
+
+func runBroadcastSender() {
+	ctx := context.Background()
+
+	email := &FlakySender{}
+	sms := &FlakySender{}
+	errSMTP := errors.New("smtp server unreachable")
+
+	broadcast := BroadcastSender{Senders: []SenderInterface{email, FailingSender{Err: errSMTP}, sms}}
+	err := SendEmail(ctx, broadcast, "market closed")
+
+	// One failure doesn't stop the others.
+	fmt.Println(email.Sent, sms.Sent) // [market closed] [market closed]
+
+	// The joined error prints every failure, one per line...
+	fmt.Println(err) // sender 1: smtp server unreachable
+
+	// ...and errors.Is looks inside all of them. Python's closest is ExceptionGroup.
+	fmt.Println(errors.Is(err, errSMTP))                     // true
+	fmt.Println(errors.Is(err, ErrCircuitOpen))              // false
+	fmt.Println(SendEmail(ctx, BroadcastSender{}, "nobody")) // <nil>, nothing failed
+}
+
+// TimedSender "decorates" any sender with timing, without touching its code.
+//
+// Notice the field has no name, just a type. That's "embedding".
+// Every method of the embedded SenderInterface gets "promoted" up,
+// so a TimedSender IS a SenderInterface already, with zero code.
+//
+// Then we write our own Send, which "overrides" the promoted one. Any
+// methods we don't write (if the interface had more) still pass straight through,
+// so you only override what you care about, like python's
+// class TimedSender(Sender) with a super().send() call.
+//
+// The embedded field's name is its type name, t.SenderInterface, that's
+// the python super() equivalent. Careful, a TimedSender{} with nothing
+// embedded has a nil SenderInterface, and calling it panics.
+type TimedSender struct {
+	SenderInterface
+
+	LastDuration time.Duration // how long the last Send took
+}
+
+func (t *TimedSender) Send(message string) error {
+	start := time.Now()
+	err := t.SenderInterface.Send(message) // call the wrapped sender, NOT t.Send (that would be us, forever)
+	t.LastDuration = time.Since(start)
+
+	log.Printf("send took %v", t.LastDuration)
+	return err
+}
+
+func runTimedSender() {
+	ctx := context.Background()
+
+	recorder := &FlakySender{}
+	timed := &TimedSender{SenderInterface: recorder}
+
+	fmt.Println(SendEmail(ctx, timed, "how long did this take?")) // <nil>, and logs send took ...
+	fmt.Println(recorder.Sent)                                    // [how long did this take?], inner still got it
+	fmt.Println(timed.LastDuration > 0)                           // true
+}
+
+// MetricsSender counts sends, errors, and how long they took, for dashboards.
+// Unlike TimedSender it's safe to share between goroutines, every field
+// it changes is a sync/atomic counter, no mutex needed.
+//
+// Latency goes in a "histogram": a row of buckets, each counting the sends
+// that took up to that long. Keeping every duration would grow forever,
+// buckets are a fixed handful of numbers, and still show "most sends
+// take under 10ms, but a few take over a second".
+type MetricsSender struct {
+	inner SenderInterface
+
+	sends        atomic.Int64
+	errors       atomic.Int64
+	totalLatency atomic.Int64                          // nanoseconds, for the average
+	buckets      [len(latencyBuckets) + 1]atomic.Int64 // one per bound, plus "slower than all of them"
+}
+
+// latencyBuckets are the upper bounds of the histogram buckets.
+var latencyBuckets = [...]time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+
+// NewMetricsSender wraps inner. It's a constructor because the zero value
+// would have a nil inner, and copying one after use would copy the counters.
+func NewMetricsSender(inner SenderInterface) *MetricsSender {
+	return &MetricsSender{inner: inner}
+}
+
+func (m *MetricsSender) Send(message string) error {
+	start := time.Now()
+	err := m.inner.Send(message)
+	took := time.Since(start)
+
+	m.sends.Add(1)
+	if err != nil {
+		m.errors.Add(1)
+	}
+	m.totalLatency.Add(int64(took))
+
+	bucket := len(latencyBuckets) // the catch all
+	for i, bound := range latencyBuckets {
+		if took <= bound {
+			bucket = i
+			break
+		}
+	}
+	m.buckets[bucket].Add(1)
+	return err
+}
+
+// MetricsSnapshot is a copy of the numbers at one moment, plain values, safe to hand around.
+type MetricsSnapshot struct {
+	Sends        int64
+	Errors       int64
+	TotalLatency time.Duration
+	Buckets      []LatencyBucket
+}
+
+// LatencyBucket counts the sends that took more than the previous bucket's UpTo,
+// and at most UpTo. The last bucket's UpTo is 0, meaning "slower than everything else".
+type LatencyBucket struct {
+	UpTo  time.Duration
+	Count int64
+}
+
+// Snapshot reads all the counters.
+//
+// Each counter is read atomically, but not all together. A Send finishing
+// in between could be in Sends and not yet in Buckets. For metrics that's fine,
+// the next snapshot catches up.
+func (m *MetricsSender) Snapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		Sends:        m.sends.Load(),
+		Errors:       m.errors.Load(),
+		TotalLatency: time.Duration(m.totalLatency.Load()),
+	}
+	for i := range m.buckets {
+		var upTo time.Duration // 0 for the catch all
+		if i < len(latencyBuckets) {
+			upTo = latencyBuckets[i]
+		}
+		snapshot.Buckets = append(snapshot.Buckets, LatencyBucket{UpTo: upTo, Count: m.buckets[i].Load()})
+	}
+	return snapshot
+}
+
+func runMetricsSender() {
+	ctx := context.Background()
+
+	// 100 goroutines sending at once through one MetricsSender.
+	metrics := NewMetricsSender(NopSender{})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = SendEmail(ctx, metrics, fmt.Sprintf("message %d", i))
+		}()
+	}
+	wg.Wait()
+
+	snapshot := metrics.Snapshot()
+	fmt.Println(snapshot.Sends, snapshot.Errors) // 100 0, none lost, even without a mutex
+	fmt.Println(snapshot.Buckets[0])             // {1ms 100}, a NopSender is quick
+
+	// Errors.
+	flaky := &FlakySender{}
+	flakyMetrics := NewMetricsSender(flaky)
+	_ = SendEmail(ctx, flakyMetrics, "works")
+	flaky.Down = true
+	_ = SendEmail(ctx, flakyMetrics, "fails")
+	snapshot = flakyMetrics.Snapshot()
+	fmt.Println(snapshot.Sends, snapshot.Errors) // 2 1
+
+	// A slow send lands in a later bucket.
+	slowMetrics := NewMetricsSender(&SlowSender{Delay: 20 * time.Millisecond})
+	_ = SendEmail(ctx, slowMetrics, "takes a while")
+	snapshot = slowMetrics.Snapshot()
+	fmt.Println(snapshot.Buckets) // [{1ms 0} {10ms 0} {100ms 1} {1s 0} {0s 0}], 0s is the catch all
+}
+
+// Mixins: embedding more than one type
+//
+// Python does mixins with multiple inheritance, class Service(LoggerMixin, MetricsMixin).
+// Go embeds as many types as you like, and all their methods get promoted.
+// Here Logger and Metrics are tiny, separate pieces you can bolt onto anything.
+type Logger struct {
+	Lines []string
+}
+
+func (l *Logger) Log(message string) {
+	l.Lines = append(l.Lines, message)
+}
+
+func (l *Logger) Report() string {
+	return fmt.Sprintf("%d log lines", len(l.Lines))
+}
+
+type Metrics struct {
+	Counts map[string]int
+}
+
+func (m *Metrics) Inc(name string) {
+	if m.Counts == nil {
+		m.Counts = map[string]int{}
+	}
+	m.Counts[name]++
+}
+
+func (m *Metrics) Report() string {
+	return fmt.Sprintf("%d requests", m.Counts["requests"])
+}
+
+// Service gets Log from Logger and Inc from Metrics, for free.
+//
+// But BOTH have a Report method. Python picks the first parent (the "MRO").
+// Go refuses to guess: s.Report() would be a compile error,
+// "ambiguous selector s.Report". It's only an error if you actually call it,
+// the struct itself is fine.
+//
+// Two ways out, both explicit, both below:
+//   - say which one you mean, s.Logger.Report()
+//   - write Service's own Report. A method on the outer type always wins
+//     over promoted ones (like TimedSender.Send above), so the ambiguity is gone.
+type Service struct {
+	*Logger
+	*Metrics
+
+	Name string
+}
+
+// Report settles the Report collision, by using both.
+func (s *Service) Report() string {
+	return s.Name + ": " + s.Logger.Report() + ", " + s.Metrics.Report()
+}
+
+func (s *Service) Handle(request string) {
+	s.Log("handled " + request) // promoted from *Logger, really s.Logger.Log
+	s.Inc("requests")           // promoted from *Metrics
+}
+
+func runMixins() {
+	// Embedded pointers start out nil, so fill them in or the first s.Log panics.
+	service := &Service{Logger: &Logger{}, Metrics: &Metrics{}, Name: "mailer"}
+	service.Handle("/send")
+	service.Handle("/send")
+
+	fmt.Println(service.Lines)              // [handled /send handled /send], fields get promoted too
+	fmt.Println(service.Counts["requests"]) // 2
+
+	fmt.Println(service.Logger.Report())  // 2 log lines
+	fmt.Println(service.Metrics.Report()) // 2 requests
+	fmt.Println(service.Report())         // mailer: 2 log lines, 2 requests
+}
+
+// Functional options
+//
+// How do you make a constructor with optional settings? Python has keyword args
+// with defaults, NewSender(name, retries=3, prefix=""). Go doesn't.
+//
+// Go libraries everywhere (grpc, zap, http clients) use "functional options" instead.
+// Each option is a func that tweaks a private config struct:
+//
+//	NewConfigurableSender("C")                                   // all defaults
+//	NewConfigurableSender("C", WithPrefix("[urgent] "))           // just the prefix
+//	NewConfigurableSender("C", WithRetries(5), WithLogger(myLog)) // mix and match, any order
+//
+// Adding a new option later doesn't break anyone's existing calls.
+type senderConfig struct {
+	retries   int                        // how many more tries after a failed delivery
+	prefix    string                     // added to the front of every message
+	logger    *log.Logger                // where to print, nil means fmt (stdout)
+	transport func(message string) error // the actual delivery (SMTP...), nil means just print
+}
+
+// Option changes one setting on a senderConfig.
+type Option func(*senderConfig)
+
+// WithRetries sets how many times to retry a failed send.
+// Less than 0 counts as 0, one try and no retries.
+func WithRetries(n int) Option {
+	return func(c *senderConfig) {
+		c.retries = max(n, 0) // a negative n would skip deliver's loop, "giving up" with a nil error
+	}
+}
+
+// WithTransport delivers messages with fn before printing them, retried on error.
+func WithTransport(fn func(message string) error) Option {
+	return func(c *senderConfig) {
+		c.transport = fn
+	}
+}
+
+// WithPrefix puts prefix in front of every message.
+func WithPrefix(prefix string) Option {
+	return func(c *senderConfig) {
+		c.prefix = prefix
+	}
+}
+
+// WithLogger prints messages with l instead of fmt.
+func WithLogger(l *log.Logger) Option {
+	return func(c *senderConfig) {
+		c.logger = l
+	}
+}
+
+// NewConfigurableSender makes a SenderB, starting from the defaults
+// and then applying each option in order.
+func NewConfigurableSender(name string, opts ...Option) *SenderB {
+	config := senderConfig{retries: 3} // the defaults
+	for _, opt := range opts {
+		opt(&config) // each option edits the config in place
+	}
+	return &SenderB{FirstName: name, config: config}
+}
+
+// deliver runs the transport, trying again up to retries more times if it fails.
+// go_6_errors.go's Retry is the fancier version, with backoff between tries.
+func (s *SenderB) deliver(message string) error {
+	if s.config.transport == nil {
+		return nil // printing can't fail
+	}
+	var err error
+	for try := 0; try <= s.config.retries; try++ { // <=, the first try isn't a retry
+		if err = s.config.transport(message); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("gave up after %d tries: %w", s.config.retries+1, err)
+}
+
+// printf prints with the configured logger, or fmt if there isn't one.
+func (s *SenderB) printf(format string, args ...any) {
+	if s.config.logger != nil {
+		s.config.logger.Printf(format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+func runConfigurableSender() {
+	plain := NewConfigurableSender("C")
+	fmt.Println(plain.config.retries, plain.config.prefix == "", plain.config.logger == nil) // 3 true true
+
+	// Only the prefix, retries and logger stay at their defaults.
+	urgent := NewConfigurableSender("D", WithPrefix("[urgent] "))
+	fmt.Println(urgent.config.retries, urgent.config.logger == nil) // 3 true
+	_ = urgent.Send("market crash")                                 // Send 1 from D: [urgent] market crash
+
+	logged := NewConfigurableSender("E", WithRetries(5), WithLogger(log.New(os.Stdout, "[email] ", 0)))
+	fmt.Println(logged.config.retries) // 5
+	_ = logged.Send("hi")              // [email] Send 1 from E: hi
+
+	// Retries only matter when delivering can fail. This mail server
+	// is down for the first 2 tries, and then it's back.
+	var tries int
+	flakySMTP := func(message string) error {
+		tries++
+		if tries <= 2 {
+			return errors.New("smtp: connection refused")
+		}
+		return nil
+	}
+	patient := NewConfigurableSender("F", WithTransport(flakySMTP)) // the default 3 retries
+	fmt.Println(patient.Send("hello"), tries)                       // Send 1 from F: hello, then <nil> 3
+
+	tries = 0
+	impatient := NewConfigurableSender("G", WithRetries(1), WithTransport(flakySMTP))
+	fmt.Println(impatient.Send("hello")) // gave up after 2 tries: smtp: connection refused
+	fmt.Println(impatient.MessageCount)  // 0, only messages that were really sent count
+
+	tries = 0
+	once := NewConfigurableSender("H", WithRetries(-1), WithTransport(flakySMTP)) // -1 is the same as 0
+	fmt.Println(once.Send("hello"))                                               // gave up after 1 tries: smtp: connection refused
+}
+
+// User is the same struct from the intro, moved up to the package level
+// so we can give it methods (types declared inside a func can't have any).
+type User struct {
+	Name     string
+	Password string
+}
+
+// String makes User a fmt.Stringer, an interface from the standard library:
+//
+//	type Stringer interface {
+//	  String() string
+//	}
+//
+// Just like SendEmail only cares that you have a Send method,
+// fmt.Println/Printf("%v")/Sprint check "do you have a String() string method?"
+// and if so, print whatever it returns instead of the raw fields.
+// Like python's __str__, but nobody declares it, you just have the method.
+//
+// Without this, fmt.Println(user) prints {Alice Gopher123}, password and all,
+// straight into the logs.
+func (u User) String() string {
+	return fmt.Sprintf("User{Name: %s, Password: ****}", u.Name)
+}
+
+func runUserStringer() {
+	aliceUser := User{Name: "Alice", Password: "Gopher123"}
+
+	fmt.Println(aliceUser)                      // User{Name: Alice, Password: ****}
+	fmt.Printf("%v %s\n", aliceUser, aliceUser) // same, both use String()
+	log.Print(aliceUser)                        // same, log uses fmt under the hood
+
+	// Works inside other things too, fmt calls String on each item.
+	fmt.Println([]User{aliceUser, {Name: "Bob", Password: "Gopher456"}}) // [User{Name: Alice, Password: ****} User{Name: Bob, Password: ****}]
+
+	// Careful, %#v is the "Go syntax" format and skips String() on purpose (it looks for GoString()).
+	// fmt.Printf("%#v", aliceUser) <-- main.User{Name:"Alice", Password:"Gopher123"} 😬
+
+	// Quick check that the password never shows up.
+	fmt.Println(strings.Contains(fmt.Sprint(aliceUser), aliceUser.Password)) // false
+}
+
+// MarshalJSON makes User a json.Marshaler, another standard library interface.
+// json.Marshal checks for it the same way fmt checks for String(),
+// so every API response or JSON log line masks the password by default.
+//
+// The alias trick, why not just call json.Marshal(u) in here?
+// Because json.Marshal(u) sees u has a MarshalJSON method... and calls it,
+// which calls json.Marshal(u), which calls MarshalJSON... forever,
+// until the stack overflows and the program crashes.
+//
+// "type alias User" makes a new type with the same fields but NONE of the
+// methods, so json.Marshal falls back to the normal field-by-field encoding.
+//
+// It's a value receiver on purpose, so both User and *User get masked.
+func (u User) MarshalJSON() ([]byte, error) {
+	type alias User // same fields, no methods, no infinite loop
+	masked := alias(u)
+	masked.Password = "****"
+	return json.Marshal(masked)
+}
+
+func runUserJSON() {
+	aliceUser := User{Name: "Alice", Password: "Gopher123"}
+
+	aliceJSON, err := json.Marshal(aliceUser)
+	if err != nil {
+		fmt.Println("could not marshal:", err)
+	}
+	fmt.Println(string(aliceJSON)) // {"Name":"Alice","Password":"****"}
+
+	// Also masked when buried inside something else, like an API response.
+	response := map[string][]*User{"users": {&aliceUser}}
+	responseJSON, _ := json.Marshal(response)
+	fmt.Println(string(responseJSON)) // {"users":[{"Name":"Alice","Password":"****"}]}
+
+	fmt.Println(strings.Contains(string(responseJSON), aliceUser.Password)) // false
+}
+
+// UserBuilder builds a User step by step, then checks it at the end.
+//
+// The intro builds structs with a literal, User{Name: "Alice", Password: "..."}.
+// That's the normal Go way, but nothing stops User{} with no name at all.
+// A builder gives you one place (Build) to validate before anyone gets a User.
+//
+// Each step returns the builder, so calls chain like JS promise .then().then():
+//
+//	user, err := NewUserBuilder().Name("Alice").Password("Gopher123").Build()
+type UserBuilder struct {
+	user User
+}
+
+// NewUserBuilder starts an empty builder.
+func NewUserBuilder() *UserBuilder {
+	return &UserBuilder{}
+}
+
+// Name sets the user's name.
+func (b *UserBuilder) Name(name string) *UserBuilder {
+	b.user.Name = name
+	return b // returning ourselves is what makes chaining work
+}
+
+// Password sets the user's password.
+func (b *UserBuilder) Password(password string) *UserBuilder {
+	b.user.Password = password
+	return b
+}
+
+// Build checks every field is filled in, and reports ALL missing fields at once.
+func (b *UserBuilder) Build() (User, error) {
+	var errs []error
+	if b.user.Name == "" {
+		errs = append(errs, errors.New("user name is required"))
+	}
+	if b.user.Password == "" {
+		errs = append(errs, errors.New("user password is required"))
+	}
+	if len(errs) > 0 {
+		return User{}, errors.Join(errs...) // zero value User, don't hand out a half-built one
+	}
+	return b.user, nil
+}
+
+func runUserBuilder() {
+	alice, err := NewUserBuilder().Name("Alice").Password("Gopher123").Build()
+	fmt.Println(alice, err) // User{Name: Alice, Password: ****} <nil>
+
+	_, err = NewUserBuilder().Password("Gopher456").Build()
+	fmt.Println(err) // user name is required
+
+	_, err = NewUserBuilder().Build()
+	fmt.Println(err) // user name is required, user password is required (one per line)
 }
 
 func main() {
 	runSenders()
 
 	runSendersInterface()
+
+	runNopSender()
+
+	runContextSendEmail()
+
+	runCircuitBreaker()
+
+	runBatchSender()
+
+	runInterfaceSegregation()
+
+	runFallbackSender()
+
+	runBroadcastSender()
+
+	runTimedSender()
+
+	runMetricsSender()
+
+	runMixins()
+
+	runConfigurableSender()
+
+	runUserStringer()
+
+	runUserJSON()
+
+	runUserBuilder()
 }
@@ -0,0 +1,396 @@
+package main
+
+//	go test go_4_structs_interfaces.go go_4_structs_interfaces_test.go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chanSender hands every message to a channel. Unlike FlakySender it's safe
+// to read from the test while BatchSender's goroutine is sending.
+type chanSender chan string
+
+func (c chanSender) Send(message string) error {
+	c <- message
+	return nil
+}
+
+// receive waits up to a second for the next message.
+func (c chanSender) receive(t *testing.T) string {
+	t.Helper()
+	select {
+	case message := <-c:
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("nothing was sent")
+		return ""
+	}
+}
+
+func TestSendEmailErrorPath(t *testing.T) {
+	errSMTP := errors.New("smtp server unreachable")
+	if err := SendEmail(context.Background(), FailingSender{Err: errSMTP}, "hi"); err != errSMTP {
+		t.Errorf("SendEmail = %v, want %v", err, errSMTP)
+	}
+	if err := SendEmail(context.Background(), NopSender{}, "hi"); err != nil {
+		t.Errorf("SendEmail(NopSender) = %v, want nil", err)
+	}
+}
+
+func TestSendEmailCancelledContext(t *testing.T) {
+	slow := &SlowSender{Delay: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := SendEmail(ctx, slow, "hurry up")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if took := time.Since(start); took >= time.Second {
+		t.Errorf("took %v, waited for the slow sender to finish", took)
+	}
+	if len(slow.Sent) != 0 {
+		t.Errorf("Sent = %q, want nothing sent", slow.Sent)
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	recorder := &FlakySender{}
+	if err := SendEmail(cancelled, recorder, "never sent"); !errors.Is(err, context.Canceled) || len(recorder.Sent) != 0 {
+		t.Errorf("already cancelled: err = %v, sent %q, want context.Canceled and nothing sent", err, recorder.Sent)
+	}
+}
+
+func TestNopSenderVsNilSender(t *testing.T) {
+	if sendPanics(NopSender{}) {
+		t.Error("NopSender.Send panicked, it should do nothing")
+	}
+	var sender SenderInterface
+	if !sendPanics(sender) {
+		t.Error("Send on a nil SenderInterface didn't panic")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)
+	flaky := &FlakySender{Down: true}
+	breaker := NewCircuitBreakerSender(flaky, 3, time.Minute)
+	breaker.Now = func() time.Time { return now } // a fake clock, moved by hand below
+
+	t.Run("closed to open", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := breaker.Send("try"); err == nil || errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("failure %d: err = %v, want the sender's own error", i+1, err)
+			}
+		}
+		flaky.Down = false
+		if err := breaker.Send("try"); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("after 3 failures: err = %v, want ErrCircuitOpen", err)
+		}
+		if len(flaky.Sent) != 0 {
+			t.Errorf("open breaker called the sender, Sent = %q", flaky.Sent)
+		}
+	})
+
+	t.Run("failed trial reopens", func(t *testing.T) {
+		flaky.Down = true
+		now = now.Add(time.Minute) // cooldown over, half-open
+		if err := breaker.Send("trial"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("trial: err = %v, want the sender's own error", err)
+		}
+		now = now.Add(30 * time.Second) // the cooldown restarted at the failed trial
+		if err := breaker.Send("too soon"); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("err = %v, want ErrCircuitOpen", err)
+		}
+	})
+
+	t.Run("half-open recovery", func(t *testing.T) {
+		flaky.Down = false
+		now = now.Add(time.Minute)
+		for _, message := range []string{"trial", "back to normal"} {
+			if err := breaker.Send(message); err != nil {
+				t.Errorf("Send(%q) = %v, want nil", message, err)
+			}
+		}
+		if got := strings.Join(flaky.Sent, ","); got != "trial,back to normal" {
+			t.Errorf("Sent = %q", got)
+		}
+	})
+}
+
+// blockingSender blocks on "slow" until release is closed, everything else goes right through.
+type blockingSender struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b blockingSender) Send(message string) error {
+	if message == "slow" {
+		close(b.started)
+		<-b.release
+	}
+	return nil
+}
+
+func TestCircuitBreakerDoesNotHoldTheLockWhileSending(t *testing.T) {
+	inner := blockingSender{started: make(chan struct{}), release: make(chan struct{})}
+	breaker := NewCircuitBreakerSender(inner, 3, time.Minute)
+	defer close(inner.release)
+
+	go func() { _ = breaker.Send("slow") }()
+	<-inner.started
+
+	done := make(chan error)
+	go func() { done <- breaker.Send("fast") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Send(fast) = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Send(fast) waited for the slow send to finish")
+	}
+}
+
+func TestBatchSender(t *testing.T) {
+	t.Run("flushes when full", func(t *testing.T) {
+		recorder := make(chanSender, 10)
+		batcher := NewBatchSender(recorder, 3, time.Hour) // the ticker never fires
+		defer batcher.Close()
+		for _, message := range []string{"a", "b", "c"} {
+			if err := batcher.Send(message); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := recorder.receive(t); got != "a\nb\nc" {
+			t.Errorf("batch = %q, want a, b and c", got)
+		}
+	})
+
+	t.Run("flushes on the interval", func(t *testing.T) {
+		recorder := make(chanSender, 10)
+		batcher := NewBatchSender(recorder, 100, 5*time.Millisecond) // never fills up
+		defer batcher.Close()
+		_ = batcher.Send("trickle 1")
+		_ = batcher.Send("trickle 2")
+		if got := recorder.receive(t); got != "trickle 1\ntrickle 2" {
+			t.Errorf("batch = %q, want both trickles", got)
+		}
+	})
+
+	t.Run("Close flushes the partial batch", func(t *testing.T) {
+		recorder := make(chanSender, 10)
+		batcher := NewBatchSender(recorder, 100, time.Hour)
+		_ = batcher.Send("last one")
+		_ = batcher.Send("really last one")
+		if err := batcher.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if got := recorder.receive(t); got != "last one\nreally last one" {
+			t.Errorf("batch = %q, want both leftovers", got)
+		}
+		if err := batcher.Send("too late"); !errors.Is(err, ErrBatchClosed) {
+			t.Errorf("Send after Close = %v, want ErrBatchClosed", err)
+		}
+		if err := batcher.Close(); err != nil {
+			t.Errorf("second Close = %v, want nil", err)
+		}
+	})
+}
+
+func TestFallbackSender(t *testing.T) {
+	t.Run("first fails, second works", func(t *testing.T) {
+		primary, backup := &FlakySender{Down: true}, &FlakySender{}
+		fallback := FallbackSender{Senders: []SenderInterface{primary, backup}}
+		if err := fallback.Send("important"); err != nil {
+			t.Fatal(err)
+		}
+		if len(primary.Sent) != 0 || len(backup.Sent) != 1 {
+			t.Errorf("primary got %q, backup got %q, want only the backup", primary.Sent, backup.Sent)
+		}
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		errSMTP, errSMS := errors.New("smtp down"), errors.New("sms down")
+		fallback := FallbackSender{Senders: []SenderInterface{FailingSender{Err: errSMTP}, FailingSender{Err: errSMS}}}
+		err := fallback.Send("important")
+		if !errors.Is(err, errSMTP) || !errors.Is(err, errSMS) {
+			t.Errorf("err = %v, want both errors inside", err)
+		}
+		if !strings.HasPrefix(fmt.Sprint(err), "all 2 senders failed") {
+			t.Errorf("err = %v, want it to say how many failed", err)
+		}
+	})
+
+	if err := (FallbackSender{}).Send("nobody"); err == nil {
+		t.Error("no senders: err = nil, want an error")
+	}
+}
+
+func TestBroadcastSender(t *testing.T) {
+	email, sms := &FlakySender{}, &FlakySender{}
+	errSMTP := errors.New("smtp server unreachable")
+	broadcast := BroadcastSender{Senders: []SenderInterface{email, FailingSender{Err: errSMTP}, sms}}
+
+	err := broadcast.Send("market closed")
+	if !errors.Is(err, errSMTP) || !strings.Contains(err.Error(), "sender 1") {
+		t.Errorf("err = %v, want sender 1's error", err)
+	}
+	if len(email.Sent) != 1 || len(sms.Sent) != 1 {
+		t.Errorf("email got %q, sms got %q, want the message in both", email.Sent, sms.Sent)
+	}
+}
+
+func TestTimedSender(t *testing.T) {
+	inner := &SlowSender{Delay: time.Millisecond}
+	timed := &TimedSender{SenderInterface: inner}
+	if err := timed.Send("how long?"); err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.Sent) != 1 || inner.Sent[0] != "how long?" {
+		t.Errorf("inner got %q, want the message", inner.Sent)
+	}
+	if timed.LastDuration < time.Millisecond {
+		t.Errorf("LastDuration = %v, want at least the 1ms send", timed.LastDuration)
+	}
+}
+
+func TestMetricsSenderConcurrentTotals(t *testing.T) {
+	metrics := NewMetricsSender(NopSender{})
+	failing := NewMetricsSender(FailingSender{Err: errors.New("down")})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); _ = metrics.Send("hi") }()
+		go func() { defer wg.Done(); _ = failing.Send("hi") }()
+	}
+	wg.Wait()
+
+	for name, test := range map[string]struct {
+		sender     *MetricsSender
+		wantErrors int64
+	}{"ok": {metrics, 0}, "failing": {failing, 50}} {
+		snapshot := test.sender.Snapshot()
+		var bucketed int64
+		for _, bucket := range snapshot.Buckets {
+			bucketed += bucket.Count
+		}
+		if snapshot.Sends != 50 || snapshot.Errors != test.wantErrors || bucketed != 50 {
+			t.Errorf("%s: %d sends, %d errors, %d in buckets, want 50, %d, 50",
+				name, snapshot.Sends, snapshot.Errors, bucketed, test.wantErrors)
+		}
+	}
+}
+
+func TestServiceMixins(t *testing.T) {
+	service := &Service{Logger: &Logger{}, Metrics: &Metrics{}, Name: "mailer"}
+	service.Handle("/send")
+	service.Handle("/send")
+
+	if len(service.Lines) != 2 || service.Counts["requests"] != 2 {
+		t.Errorf("Lines = %q, Counts = %v, want 2 of each", service.Lines, service.Counts)
+	}
+	if got := service.Logger.Report(); got != "2 log lines" {
+		t.Errorf("Logger.Report() = %q", got)
+	}
+	if got := service.Report(); got != "mailer: 2 log lines, 2 requests" {
+		t.Errorf("Report() = %q, want Service's own Report to settle the collision", got)
+	}
+}
+
+// sendAll only needs a Send, so something with nothing but Send works.
+func TestSendAllTakesASendOnlyType(t *testing.T) {
+	recorder := &FlakySender{}
+	if err := sendAll(recorder, "one", "two"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(recorder.Sent, ",") != "one,two" {
+		t.Errorf("Sent = %q", recorder.Sent)
+	}
+
+	recorder.Down = true
+	if err := sendAll(recorder, "three"); err == nil {
+		t.Error("sendAll to a down sender = nil, want the error")
+	}
+}
+
+func TestConfigurableSenderOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantRetries int
+		wantPrefix  string
+	}{
+		{"defaults", nil, 3, ""},
+		{"prefix only", []Option{WithPrefix("[urgent] ")}, 3, "[urgent] "},
+		{"retries only", []Option{WithRetries(5)}, 5, ""},
+		{"negative retries", []Option{WithRetries(-2)}, 0, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := NewConfigurableSender("C", test.opts...)
+			config := sender.config
+			if config.retries != test.wantRetries || config.prefix != test.wantPrefix || config.logger != nil || config.transport != nil {
+				t.Errorf("config = %+v, want retries %d and prefix %q, the rest nil", config, test.wantRetries, test.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestConfigurableSenderRetries(t *testing.T) {
+	errRefused := errors.New("smtp: connection refused")
+	tries := 0
+	alwaysDown := func(string) error { tries++; return errRefused }
+
+	sender := NewConfigurableSender("G", WithRetries(-1), WithTransport(alwaysDown))
+	err := sender.Send("hello")
+	if !errors.Is(err, errRefused) || tries != 1 {
+		t.Errorf("err = %v after %d tries, want %v after 1", err, tries, errRefused)
+	}
+	if sender.MessageCount != 0 {
+		t.Errorf("MessageCount = %d, a failed send shouldn't count", sender.MessageCount)
+	}
+}
+
+func TestUserNeverShowsThePassword(t *testing.T) {
+	user := User{Name: "Alice", Password: "Gopher123"}
+	for _, format := range []string{"%v", "%s", "%+v"} {
+		if got := fmt.Sprintf(format, user); strings.Contains(got, user.Password) {
+			t.Errorf("Sprintf(%q) = %q, shows the password", format, got)
+		}
+	}
+
+	data, err := json.Marshal([]*User{&user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Name":"Alice"`) || strings.Contains(string(data), user.Password) {
+		t.Errorf("json = %s, want the name and not the password", data)
+	}
+}
+
+func TestUserBuilder(t *testing.T) {
+	user, err := NewUserBuilder().Name("Alice").Password("Gopher123").Build()
+	if err != nil || user != (User{Name: "Alice", Password: "Gopher123"}) {
+		t.Errorf("Build() = %v, %v, want Alice", user, err)
+	}
+
+	user, err = NewUserBuilder().Password("Gopher456").Build()
+	if err == nil || err.Error() != "user name is required" || user != (User{}) {
+		t.Errorf("no name: Build() = %#v, %v, want a zero User and the name error", user, err)
+	}
+
+	_, err = NewUserBuilder().Build()
+	if err == nil || !strings.Contains(err.Error(), "name") || !strings.Contains(err.Error(), "password") {
+		t.Errorf("empty: err = %v, want both fields reported", err)
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// User is the same struct from the intro.
+type User struct {
+	Name     string
+	Password string
+}
+
+func main() {
+	// Generics (Go 1.18+) let one function work over many types,
+	// like Python's duck typing but checked by the compiler.
+	//
+	// Before generics you had to write MaxInt, MaxFloat, MaxString...
+	// or use interface{} and reflection (slow, and runtime errors).
+	//
+	// Same slices as the intro, now with one Max and one MinSlice for all.
+	numbersSlice := []int{2, 3, 5, 7, 11, 13}
+	floatsSlice := []float64{42.4, -1.5, 3.14}
+	wordsSlice := []string{"foo", "bar", "bazz"}
+
+	fmt.Println(Max(3, 7))          // 7, T is inferred as int
+	fmt.Println(Max(-3, -7))        // -3
+	fmt.Println(Max(4.5, 4.5))      // 4.5, equal values are fine
+	fmt.Println(Max("foo", "bazz")) // foo, strings compare alphabetically
+	// Max(3, "foo")                <-- compile error, both have to be the same T
+
+	minNum, ok := MinSlice(numbersSlice)
+	fmt.Println(minNum, ok) // 2 true
+
+	minFloat, ok := MinSlice(floatsSlice)
+	fmt.Println(minFloat, ok) // -1.5 true
+
+	minWord, ok := MinSlice(wordsSlice)
+	fmt.Println(minWord, ok) // bar true
+
+	// The empty case, like python's min([]) but without the exception.
+	minEmpty, ok := MinSlice([]int{})
+	if !ok {
+		fmt.Println("no min for an empty slice, got the zero value", minEmpty) // 0
+	}
+
+	// Sets
+	//
+	// Go has no built-in set (python's set()), but the intro showed map keys
+	// can be any comparable type, even structs. A map with empty values is
+	// a set, and generics let us write it once for every type.
+	words := strings.Split("one,two,two,three,one", ",")
+	uniqueWords := NewSet(words...)
+	fmt.Println(uniqueWords.Len())       // 3
+	fmt.Println(uniqueWords.Has("two"))  // true
+	fmt.Println(uniqueWords.Has("four")) // false
+
+	// Struct keys work too, two Users are "equal" when all fields are equal.
+	aliceUser := User{Name: "Alice", Password: "Gopher123"}
+	bobUser := User{Name: "Bob", Password: "Gopher456"}
+	cindyUser := User{Name: "Cindy", Password: "Gopher789"}
+
+	admins := NewSet(aliceUser, bobUser)
+	admins.Add(User{Name: "Alice", Password: "Gopher123"}) // already in there, no dupe
+	fmt.Println(admins.Len())                              // 2
+
+	editors := NewSet(bobUser, cindyUser)
+	fmt.Println(admins.Union(editors).Len())     // 3, Alice Bob Cindy   (python: admins | editors)
+	fmt.Println(admins.Intersect(editors).Len()) // 1, Bob               (python: admins & editors)
+
+	admins.Remove(bobUser)
+	fmt.Println(admins.Has(bobUser))                  // false
+	fmt.Println(admins.Intersect(editors).Len() == 0) // true
+
+	// Ordered maps
+	//
+	// The intro warned that ranging over a map is in random order.
+	// Python 3.7+ dicts remember insertion order, Go maps never will.
+	// When you need that, keep the keys in a slice alongside the map.
+	nameToAge := NewOrderedMap[string, int]()
+	nameToAge.Set("Bob", 42)
+	nameToAge.Set("Alice", 33)
+	nameToAge.Set("Cindy", 27)
+	nameToAge.Set("Bob", 43) // updates Bob, he keeps his original spot
+	nameToAge.Delete("Alice")
+
+	// Same order every run: Bob 43, Cindy 27
+	for _, name := range nameToAge.Keys() {
+		age, _ := nameToAge.Get(name)
+		fmt.Println(name, age)
+	}
+
+	// Iterators (Go 1.23+)
+	//
+	// Python has generators, functions that "yield" values one at a time
+	// into a for loop. Go 1.23 lets you range over a function to do the same.
+	for v := range Countdown(3) {
+		fmt.Println(v) // 3, 2, 1
+	}
+
+	// Like python's enumerate(), but for any slice type.
+	for i, word := range Enumerate(wordsSlice) {
+		fmt.Println(i, word) // 0 foo, 1 bar, 2 bazz
+	}
+
+	// break works like normal, the iterator just stops early.
+	for v := range Countdown(1000000) {
+		if v == 999998 {
+			break
+		}
+		fmt.Println(v) // 1000000, 999999, then stop. The other 999997 never run.
+	}
+
+	// Ring buffers
+	//
+	// go_3_goroutines.go talks a lot about channel buffers, a fixed amount of room.
+	// A ring buffer is fixed room too, but when it's full it overwrites the oldest
+	// item instead of blocking. Perfect for "the last N things that happened".
+	lastSeen := NewRingBuffer[string](3)
+	for _, symbol := range []string{"AAPL", "GOOG", "FB", "AMZN", "AAPL"} {
+		lastSeen.Push(symbol)
+	}
+	fmt.Println(lastSeen.Slice()) // [FB AMZN AAPL], the last 3 (AAPL and GOOG got overwritten)
+
+	oldest, ok := lastSeen.Pop()
+	fmt.Println(oldest, ok, lastSeen.Len()) // FB true 2
+
+	lastSeen.Pop()
+	lastSeen.Pop()
+	_, ok = lastSeen.Pop()
+	fmt.Println(ok, lastSeen.Slice()) // false [], drained
+}
+
+// Max returns the larger of a and b.
+//
+// The [T cmp.Ordered] part is the "type parameter", read it as
+// "for any type T that supports < <= >= >".
+// cmp.Ordered covers ints, uints, floats and strings.
+//
+//	type parameter   constraint
+//	 |                |
+//	\/               \/
+//	[T               cmp.Ordered]
+func Max[T cmp.Ordered](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// MinSlice returns the smallest item in s.
+//
+// What's the min of an empty slice? Python raises ValueError,
+// JS Math.min() gives Infinity. Go has neither (no exceptions, and
+// there's no "Infinity" for strings), so we use the "zero value and ok"
+// pattern, the same one used when reading maps:
+//
+//	val, ok := MinSlice(s)
+//	if !ok { ... s was empty, val is just the zero value (0, "", etc) }
+func MinSlice[T cmp.Ordered](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T // 0 for numbers, "" for strings
+		return zero, false
+	}
+
+	smallest := s[0]
+	for _, v := range s[1:] {
+		if v < smallest {
+			smallest = v
+		}
+	}
+	return smallest, true
+}
+
+// Set is a generic set, backed by a map.
+//
+// Why map[T]struct{} and not map[T]bool?
+// struct{} takes zero bytes of memory, bool takes one per key.
+// It also makes it clear there's no meaningful value, only keys.
+//
+// T must be "comparable" (works with ==) to be a map key, so
+// ints, strings, and structs of those are fine, slices are not.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet makes a set, optionally filled with the given items.
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add puts item in the set, adding it twice is a no-op.
+func (s *Set[T]) Add(item T) {
+	s.items[item] = struct{}{}
+}
+
+// Has checks if item is in the set.
+func (s *Set[T]) Has(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+// Remove takes item out of the set, ignores it if not there.
+func (s *Set[T]) Remove(item T) {
+	delete(s.items, item)
+}
+
+// Len is how many items are in the set.
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Union returns a new set with everything in either set.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for item := range s.items {
+		result.Add(item)
+	}
+	for item := range other.items {
+		result.Add(item)
+	}
+	return result
+}
+
+// Intersect returns a new set with only the items in both sets.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for item := range s.items {
+		if other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// OrderedMap is a map that remembers insertion order, like a python dict.
+//
+// K has to be comparable to be a map key, V can be anything at all.
+type OrderedMap[K comparable, V any] struct {
+	values map[K]V
+	order  []K
+}
+
+// NewOrderedMap makes an empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{values: map[K]V{}}
+}
+
+// Set adds or updates key. Updating an existing key does not move it.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if _, exists := m.values[key]; !exists {
+		m.order = append(m.order, key) // only new keys go on the end
+	}
+	m.values[key] = value
+}
+
+// Get reads key, with ok false if it isn't there.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete removes key, ignores it if not there.
+//
+// Removing from the order slice is a scan, O(n). Fine for teaching and
+// small maps, see the linked list in container/list if you need faster.
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.order {
+		if k == key {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys in the order they were first added.
+//
+// It's a copy, so callers can't mess up our order by editing it.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, len(m.order))
+	copy(keys, m.order)
+	return keys
+}
+
+// Countdown yields n, n-1, ... 1.
+//
+// iter.Seq[int] is just a func type:
+//
+//	func(yield func(int) bool)
+//
+// For "for v := range Countdown(3) { body }", Go turns body into the yield func.
+// Every time we call yield(v), the loop body runs once with v.
+//
+// yield returns false when the loop is done early (break, return...).
+// When that happens we MUST stop and return, calling yield again panics.
+// Python generators do this for you, Go makes you check.
+func Countdown(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := n; i > 0; i-- {
+			if !yield(i) {
+				return // the loop broke out, stop
+			}
+		}
+	}
+}
+
+// Enumerate yields each index and item of s, like python's enumerate(s).
+//
+// iter.Seq2 is the two value version, for "for i, v := range ...".
+// (range over a slice already gives you i too, this is to show the mechanics.)
+func Enumerate[T any](s []T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, item := range s {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// RingBuffer holds the most recent capacity items, oldest first.
+//
+// It's a slice we use in a circle: start is where the oldest item is,
+// and the index wraps back around to 0 with % (modulo).
+//
+//	capacity 3, after pushing A B C D:
+//	 [D][B][C]
+//	     ^ start, oldest is B, then C, then D (wrapped around to 0)
+//
+// Not safe for multiple goroutines, guard it with a sync.Mutex if you share it.
+type RingBuffer[T any] struct {
+	items []T
+	start int // index of the oldest item
+	size  int // how many items are in it right now
+}
+
+// NewRingBuffer makes a RingBuffer that holds up to capacity items.
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	return &RingBuffer[T]{items: make([]T, capacity)}
+}
+
+// Push adds item as the newest, overwriting the oldest when full.
+func (r *RingBuffer[T]) Push(item T) {
+	if len(r.items) == 0 {
+		return // a zero capacity buffer holds nothing
+	}
+	end := (r.start + r.size) % len(r.items) // one past the newest, wrapping around
+	r.items[end] = item
+
+	if r.size < len(r.items) {
+		r.size++
+	} else {
+		r.start = (r.start + 1) % len(r.items) // full, we just overwrote the oldest
+	}
+}
+
+// Pop removes and returns the oldest item, with ok false if it's empty.
+func (r *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if r.size == 0 {
+		return zero, false
+	}
+	item := r.items[r.start]
+	r.items[r.start] = zero // don't hold onto it, let the garbage collector have it
+	r.start = (r.start + 1) % len(r.items)
+	r.size--
+	return item, true
+}
+
+// Len is how many items are in it right now.
+func (r *RingBuffer[T]) Len() int {
+	return r.size
+}
+
+// Slice copies the items out, oldest first.
+func (r *RingBuffer[T]) Slice() []T {
+	result := make([]T, 0, r.size)
+	for i := 0; i < r.size; i++ {
+		result = append(result, r.items[(r.start+i)%len(r.items)])
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package main
+
+//	go test go_5_generics.go go_5_generics_test.go
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMax(t *testing.T) {
+	if got := Max(-3, -7); got != -3 {
+		t.Errorf("Max(-3, -7) = %d, want -3", got)
+	}
+	if got := Max(2.5, 2.5); got != 2.5 {
+		t.Errorf("Max(2.5, 2.5) = %v, want 2.5", got)
+	}
+	if got := Max("apple", "banana"); got != "banana" {
+		t.Errorf("Max(apple, banana) = %q, want banana", got)
+	}
+}
+
+func TestMinSlice(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []int
+		want   int
+		wantOK bool
+	}{
+		{"negatives", []int{-1, -9, 4}, -9, true},
+		{"all equal", []int{7, 7, 7}, 7, true},
+		{"one item", []int{42}, 42, true},
+		{"empty", []int{}, 0, false},
+		{"nil", nil, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := MinSlice(test.input)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("MinSlice(%v) = %d, %v, want %d, %v", test.input, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+
+	if got, ok := MinSlice([]string{}); got != "" || ok {
+		t.Errorf("MinSlice(no strings) = %q, %v, want \"\", false", got, ok)
+	}
+}
+
+func TestSetOfUsers(t *testing.T) {
+	alice := User{Name: "Alice", Password: "Gopher123"}
+	bob := User{Name: "Bob", Password: "Gopher456"}
+	carol := User{Name: "Carol", Password: "Gopher789"}
+
+	// Structs are compared field by field, so an equal copy is the same member.
+	s := NewSet(alice, bob, User{Name: "Alice", Password: "Gopher123"})
+	if s.Len() != 2 || !s.Has(User{Name: "Alice", Password: "Gopher123"}) {
+		t.Errorf("Len() = %d, want 2 with Alice in it", s.Len())
+	}
+	if s.Has(User{Name: "Alice", Password: "other"}) {
+		t.Error("a different password is a different User")
+	}
+
+	other := NewSet(bob, carol)
+	if union := s.Union(other); union.Len() != 3 || !union.Has(alice) || !union.Has(carol) {
+		t.Errorf("Union has %d users, want alice, bob and carol", union.Len())
+	}
+	if both := s.Intersect(other); both.Len() != 1 || !both.Has(bob) {
+		t.Errorf("Intersect has %d users, want only bob", both.Len())
+	}
+	if s.Len() != 2 || other.Len() != 2 {
+		t.Error("Union and Intersect must not change either set")
+	}
+
+	s.Remove(bob)
+	s.Remove(carol) // not in it, ignored
+	if s.Len() != 1 || s.Has(bob) {
+		t.Errorf("after Remove: Len() = %d, Has(bob) = %v, want 1, false", s.Len(), s.Has(bob))
+	}
+}
+
+func TestOrderedMapKeepsInsertionOrder(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	for i, key := range []string{"zebra", "apple", "mango", "kiwi"} {
+		m.Set(key, i)
+	}
+	m.Set("zebra", 100) // an update doesn't move it
+	m.Delete("mango")
+	m.Delete("nope") // not there, ignored
+
+	want := []string{"zebra", "apple", "kiwi"}
+	for run := 0; run < 10; run++ { // the same every time, unlike ranging over a map
+		if got := m.Keys(); !slices.Equal(got, want) {
+			t.Fatalf("Keys() = %q, want %q", got, want)
+		}
+	}
+	if v, ok := m.Get("zebra"); v != 100 || !ok {
+		t.Errorf("Get(zebra) = %d, %v, want 100, true", v, ok)
+	}
+	if _, ok := m.Get("mango"); ok {
+		t.Error("Get(mango) found a deleted key")
+	}
+
+	keys := m.Keys()
+	keys[0] = "changed"
+	if m.Keys()[0] != "zebra" {
+		t.Error("editing the Keys() result changed the map's order")
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	var all []int
+	for n := range Countdown(4) {
+		all = append(all, n)
+	}
+	if !slices.Equal(all, []int{4, 3, 2, 1}) {
+		t.Errorf("full loop = %v, want [4 3 2 1]", all)
+	}
+
+	// Breaking out early must stop the iterator, calling yield again would panic.
+	var early []int
+	for n := range Countdown(100) {
+		if n < 98 {
+			break
+		}
+		early = append(early, n)
+	}
+	if !slices.Equal(early, []int{100, 99, 98}) {
+		t.Errorf("early break = %v, want [100 99 98]", early)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	letters := []string{"a", "b", "c"}
+	var got []string
+	for i, letter := range Enumerate(letters) {
+		if i == 2 {
+			break
+		}
+		got = append(got, string(rune('0'+i))+letter)
+	}
+	if !slices.Equal(got, []string{"0a", "1b"}) {
+		t.Errorf("got %q, want [0a 1b]", got)
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("overwrites the oldest when full", func(t *testing.T) {
+		ring := NewRingBuffer[string](3)
+		for _, s := range []string{"A", "B", "C", "D", "E"} {
+			ring.Push(s)
+		}
+		if got := ring.Slice(); !slices.Equal(got, []string{"C", "D", "E"}) || ring.Len() != 3 {
+			t.Errorf("Slice() = %q, Len() = %d, want [C D E] and 3", got, ring.Len())
+		}
+	})
+
+	t.Run("indexes wrap around", func(t *testing.T) {
+		ring := NewRingBuffer[int](3)
+		for i := 1; i <= 10; i++ { // push and pop around the circle several times
+			ring.Push(i)
+			if i%2 == 0 {
+				ring.Pop()
+			}
+		}
+		if got := ring.Slice(); !slices.Equal(got, []int{9, 10}) {
+			t.Errorf("Slice() = %v, want [9 10]", got)
+		}
+	})
+
+	t.Run("drains to empty", func(t *testing.T) {
+		ring := NewRingBuffer[int](2)
+		ring.Push(1)
+		ring.Push(2)
+		for _, want := range []int{1, 2} {
+			if got, ok := ring.Pop(); got != want || !ok {
+				t.Errorf("Pop() = %d, %v, want %d, true", got, ok, want)
+			}
+		}
+		if got, ok := ring.Pop(); got != 0 || ok || ring.Len() != 0 {
+			t.Errorf("Pop() on empty = %d, %v, want 0, false", got, ok)
+		}
+	})
+
+	t.Run("zero capacity", func(t *testing.T) {
+		ring := NewRingBuffer[int](0)
+		ring.Push(1)
+		if _, ok := ring.Pop(); ok || ring.Len() != 0 {
+			t.Error("a zero capacity buffer should stay empty")
+		}
+	})
+}
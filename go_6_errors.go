@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Retries with backoff
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Networks flake, dbs restart, APIs rate limit you. Instead of sprinkling
+	// "for i := 0; i < 3; i++ { ... time.Sleep ... }" everywhere, wrap it up once.
+	ctx := context.Background()
+
+	// Succeeds on the 3rd try.
+	calls := 0
+	err := Retry(ctx, 5, 10*time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return RetryableError{errors.New("flaky network")} // worth another go
+		}
+		return nil
+	})
+	fmt.Println(err, calls) // <nil> 3
+
+	// Never succeeds, gives up after 3 attempts.
+	err = Retry(ctx, 3, 10*time.Millisecond, func() error {
+		return RetryableError{errors.New("db is down")}
+	})
+	fmt.Println(err) // gave up after 3 attempts: db is down
+
+	// Some errors will never get better. A wrong password is still wrong in 5 seconds,
+	// retrying just wastes time (and might lock the account).
+	calls = 0
+	errBadPassword := errors.New("bad password")
+	err = Retry(ctx, 5, 10*time.Millisecond, func() error {
+		calls++
+		return fmt.Errorf("logging in: %w", errBadPassword) // not a RetryableError, so permanent
+	})
+	fmt.Println(err, calls)                     // logging in: bad password 1, no retries
+	fmt.Println(errors.Is(err, errBadPassword)) // true, handed back untouched
+
+	// isRetryable finds it even wrapped, errors.As digs through %w.
+	fmt.Println(isRetryable(fmt.Errorf("fetching user: %w", RetryableError{errors.New("timeout")}))) // true
+	fmt.Println(isRetryable(errBadPassword))                                                         // false
+
+	// Cancelled: someone upstream gave up on us (user closed the tab,
+	// server shutting down), so stop retrying right away.
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = Retry(cancelCtx, 100, time.Second, func() error {
+		return RetryableError{errors.New("still down")}
+	})
+	fmt.Println(errors.Is(err, context.DeadlineExceeded)) // true, returned after ~50ms, not 100 tries
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Closing lots of things
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	errDiskFull := errors.New("disk full")
+	errBrokenPipe := errors.New("broken pipe")
+
+	err = closeAll(
+		&fakeCloser{name: "db"},
+		&fakeCloser{name: "log file", err: errDiskFull},
+		&fakeCloser{name: "cache"},
+		&fakeCloser{name: "socket", err: errBrokenPipe},
+	)
+	fmt.Println(err)
+	// closing log file: disk full
+	// closing socket: broken pipe
+	fmt.Println(errors.Is(err, errDiskFull), errors.Is(err, errBrokenPipe)) // true true, both are in there
+
+	fmt.Println(closeAll(&fakeCloser{name: "db"})) // <nil>
+
+	// With defer, the error from closing still reaches the caller.
+	fmt.Println(exportReport(nil))           // closing output: disk full
+	fmt.Println(exportReport(errBrokenPipe)) // writing report: broken pipe, and closing output: disk full on the next line
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Sentinel errors vs error types
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Style 1, a sentinel: compare against one known error value with errors.Is.
+	_, err = findUser("zed")
+	fmt.Println(err)                         // loading profile: user not found
+	fmt.Println(errors.Is(err, ErrNotFound)) // true, even though it's wrapped
+	fmt.Println(err == ErrNotFound)          // false! == only checks the outside, use errors.Is
+
+	// Style 2, an error type: errors.As pulls it out, with its fields.
+	_, err = findUserTyped("zed")
+	fmt.Println(err) // loading profile: user "zed" not found
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		fmt.Println(notFound.Resource, notFound.ID) // user zed, the details are right there
+	}
+	fmt.Println(errors.Is(err, ErrNotFound)) // true, NotFoundError's Is method says it counts as one
+
+	// Found, no error in either style.
+	alice, err := findUser("alice")
+	fmt.Println(alice.Name, err) // Alice <nil>
+}
+
+// Retry calls fn until it returns nil, up to attempts times.
+// Only errors marked retryable (see RetryableError) are tried again, anything
+// else is permanent and comes straight back.
+//
+// Between attempts it waits with "exponential backoff":
+//
+//	base, 2*base, 4*base, 8*base ...
+//
+// plus some random "jitter" on top. Why the jitter? If 1000 clients all fail
+// at the same moment and all retry at exactly 1s, 2s, 4s, they hammer the
+// server in sync every time (the "thundering herd"). Random jitter spreads them out.
+//
+// If ctx is cancelled while waiting, we stop right away and return the ctx error.
+// Errors are wrapped with %w so callers can still errors.Is/errors.As the original.
+func Retry(ctx context.Context, attempts int, base time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1 // always try at least once
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !isRetryable(err) {
+			return err // permanent, trying again won't help
+		}
+		if attempt == attempts {
+			break // no point sleeping after the last try
+		}
+
+		backoff := base << (attempt - 1) // base * 2^(attempt-1)
+		jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+
+		// Like time.Sleep, but wakes up early if ctx is cancelled.
+		timer := time.NewTimer(backoff + jitter)
+		select {
+		case <-ctx.Done():
+			timer.Stop() // ALWAYS stop timers you abandon
+			return fmt.Errorf("cancelled after %d attempts: %w (last error: %w)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+
+	return fmt.Errorf("gave up after %d attempts: %w", attempts, err)
+}
+
+// RetryableError marks an error as worth retrying: a timeout, a 503, a dropped connection.
+//
+//	return RetryableError{err}
+//
+// Embedding error gives it an Error method for free (promotion, see
+// TimedSender in go_4_structs_interfaces.go), the message is the inner error's.
+//
+// Why mark the retryable ones and not the permanent ones? An error we
+// didn't think of should fail fast, not quietly get retried 5 times.
+type RetryableError struct {
+	error
+}
+
+// Unwrap lets errors.Is/As see the error inside, so wrapping doesn't hide it.
+func (e RetryableError) Unwrap() error {
+	return e.error
+}
+
+// isRetryable is true if a RetryableError is anywhere in err's chain.
+//
+// errors.As walks the chain (every %w and Unwrap), and if it finds something
+// of target's type, copies it into target and says true. Python's
+// except RetryableError: matches subclasses, Go matches the wrapped chain instead.
+func isRetryable(err error) bool {
+	var retryable RetryableError
+	return errors.As(err, &retryable)
+}
+
+// User is the same struct from the intro.
+type User struct {
+	Name     string
+	Password string
+}
+
+var users = map[string]User{"alice": {Name: "Alice", Password: "Gopher123"}}
+
+// ErrNotFound is a "sentinel" error, one value made once, that callers compare against.
+// The standard library is full of them: io.EOF, sql.ErrNoRows, os.ErrNotExist.
+// Like raising a specific exception class in python, but it's a value, not a type.
+//
+// Good when the caller only needs to know WHAT happened. The catch: it can't
+// carry details, every "not found" is the same error, so details go in the
+// wrapping message, where code can't get at them.
+var ErrNotFound = errors.New("not found")
+
+// findUser uses the sentinel. The caller checks errors.Is(err, ErrNotFound).
+func findUser(name string) (User, error) {
+	user, ok := users[name]
+	if !ok {
+		return User{}, fmt.Errorf("loading profile: user %w", ErrNotFound) // %w can go anywhere in the string
+	}
+	return user, nil
+}
+
+// NotFoundError is an error TYPE, it carries the details as fields.
+// Any type with an Error() string method is an error.
+//
+// Good when the caller needs more than yes/no: which thing was missing,
+// a status code, a retry-after time.
+type NotFoundError struct {
+	Resource string // "user", "order"...
+	ID       string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", e.Resource, e.ID)
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for a NotFoundError too, so callers
+// who only care "was it not found?" can use either style. errors.Is calls it for us.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// findUserTyped uses the error type. The caller checks errors.As(err, &notFound).
+//
+// It returns a *NotFoundError, a pointer, like most error types. The
+// caller's errors.As target has to be a *NotFoundError too, the types must match.
+func findUserTyped(name string) (User, error) {
+	user, ok := users[name]
+	if !ok {
+		return User{}, fmt.Errorf("loading profile: %w", &NotFoundError{Resource: "user", ID: name})
+	}
+	return user, nil
+}
+
+// closeAll closes every closer, even if some fail, and returns all the errors together.
+//
+// The usual "defer f.Close()" throws Close's error away. Mostly fine for
+// files you only read, but closing a file you WROTE is when buffered data
+// really hits the disk, a "disk full" there means your data is gone.
+//
+// errors.Join (Go 1.20) keeps every error, nil if they were all nil.
+// The joined error prints one per line, and errors.Is/As check each one.
+func closeAll(closers ...io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exportReport shows the defer pattern. err is a NAMED result, so the
+// deferred func can still change what we return, after the return statement ran.
+func exportReport(writeErr error) (err error) {
+	output := &fakeCloser{name: "output", err: errors.New("disk full")}
+	scratch := &fakeCloser{name: "scratch"}
+	defer func() {
+		err = errors.Join(err, closeAll(output, scratch)) // keep the write error AND the close errors
+	}()
+
+	if writeErr != nil {
+		return fmt.Errorf("writing report: %w", writeErr)
+	}
+	return nil
+}
+
+// fakeCloser pretends to be a file or connection, Close fails with err.
+type fakeCloser struct {
+	name string
+	err  error
+}
+
+func (f *fakeCloser) Close() error {
+	if f.err != nil {
+		return fmt.Errorf("closing %s: %w", f.name, f.err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+//	go test go_6_errors.go go_6_errors_test.go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errTemporary = errors.New("503 service unavailable")
+
+// flakyUntil fails with a retryable error until the nth call.
+func flakyUntil(n int, calls *int) func() error {
+	return func() error {
+		*calls++
+		if *calls < n {
+			return RetryableError{errTemporary}
+		}
+		return nil
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("succeeds on the 3rd try", func(t *testing.T) {
+		calls := 0
+		if err := Retry(context.Background(), 5, time.Millisecond, flakyUntil(3, &calls)); err != nil {
+			t.Fatalf("Retry = %v, want nil", err)
+		}
+		if calls != 3 {
+			t.Errorf("%d calls, want 3", calls)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), 4, time.Millisecond, flakyUntil(100, &calls))
+		if calls != 4 || !errors.Is(err, errTemporary) || !strings.Contains(err.Error(), "gave up after 4 attempts") {
+			t.Errorf("%d calls, err = %v, want 4 calls and the last error", calls, err)
+		}
+	})
+
+	t.Run("stops when ctx is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		calls := 0
+		start := time.Now()
+		err := Retry(ctx, 10, time.Hour, flakyUntil(100, &calls)) // would wait an hour before the 2nd try
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errTemporary) {
+			t.Errorf("err = %v, want both the ctx error and the last error", err)
+		}
+		if calls != 1 || time.Since(start) > time.Second {
+			t.Errorf("%d calls in %v, want 1 call and a quick return", calls, time.Since(start))
+		}
+	})
+
+	t.Run("permanent errors aren't retried", func(t *testing.T) {
+		permanent := errors.New("401 unauthorized")
+		calls := 0
+		err := Retry(context.Background(), 5, time.Millisecond, func() error { calls++; return permanent })
+		if err != permanent || calls != 1 {
+			t.Errorf("%d calls, err = %v, want 1 call and %v as is", calls, err, permanent)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"marked", RetryableError{errTemporary}, true},
+		{"marked and wrapped", errors.Join(errors.New("other"), RetryableError{errTemporary}), true},
+		{"plain", errTemporary, false},
+		{"nil", nil, false},
+	}
+	for _, test := range tests {
+		if got := isRetryable(test.err); got != test.want {
+			t.Errorf("%s: isRetryable(%v) = %v, want %v", test.name, test.err, got, test.want)
+		}
+	}
+	if !errors.Is(RetryableError{errTemporary}, errTemporary) {
+		t.Error("errors.Is can't see through RetryableError")
+	}
+}
+
+func TestSentinelAndTypedErrors(t *testing.T) {
+	_, err := findUser("zed")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("findUser: err = %v, want it to wrap ErrNotFound", err)
+	}
+
+	_, err = findUserTyped("zed")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.Resource != "user" || notFound.ID != "zed" {
+		t.Errorf("findUserTyped: err = %v, want a *NotFoundError for user zed", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("a NotFoundError should also count as ErrNotFound")
+	}
+
+	for _, find := range []func(string) (User, error){findUser, findUserTyped} {
+		if user, err := find("alice"); err != nil || user.Name != "Alice" {
+			t.Errorf("find(alice) = %v, %v, want Alice", user, err)
+		}
+	}
+}
+
+func TestCloseAll(t *testing.T) {
+	errDisk, errNet := errors.New("disk full"), errors.New("connection reset")
+	err := closeAll(
+		&fakeCloser{name: "file", err: errDisk},
+		&fakeCloser{name: "ok"},
+		&fakeCloser{name: "conn", err: errNet},
+	)
+	if !errors.Is(err, errDisk) || !errors.Is(err, errNet) {
+		t.Errorf("err = %v, want both close errors", err)
+	}
+	for _, name := range []string{"closing file", "closing conn"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("err = %q, want it to mention %q", err, name)
+		}
+	}
+
+	if err := closeAll(&fakeCloser{name: "ok"}, io.NopCloser(nil)); err != nil {
+		t.Errorf("nothing failed: err = %v, want nil", err)
+	}
+}
+
+func TestExportReportKeepsEveryError(t *testing.T) {
+	errWrite := errors.New("permission denied")
+	err := exportReport(errWrite)
+	if !errors.Is(err, errWrite) || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("err = %v, want the write error and the close error", err)
+	}
+}
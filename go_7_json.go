@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User is the same struct from the intro, with "struct tags" added.
+//
+// The `json:"name"` tag says "call this field name in JSON".
+// Without tags the JSON keys are the Go field names, "Name" and "Password".
+//
+// Only Capitalized (exported) fields are read/written by encoding/json,
+// a lowercase field is invisible to it.
+type User struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// JSON basics
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+
+	// Struct to JSON, like python json.dumps / JS JSON.stringify
+	aliceJSON, err := json.Marshal(User{Name: "Alice", Password: "Gopher123"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(aliceJSON)) // {"name":"Alice","password":"Gopher123"}
+
+	// JSON to struct, like python json.loads / JS JSON.parse
+	// Pass a pointer (&) so Unmarshal can fill in our variable.
+	var bob User
+	if err := json.Unmarshal([]byte(`{"name":"Bob","password":"Gopher456"}`), &bob); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(bob.Name) // Bob
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// JSON streaming
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// strings.NewReader turns a string into an io.Reader, the same thing
+	// you'd get from a file (os.Open) or an http response body.
+	usersJSON := `[
+		{"name": "Alice", "password": "Gopher123"},
+		{"name": "Bob",   "password": "Gopher456"},
+		{"name": "Cindy", "password": "Gopher789"}
+	]`
+	users, err := decodeUsers(strings.NewReader(usersJSON))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(users), users[2].Name) // 3 Cindy
+
+	_, err = decodeUsers(strings.NewReader(`{"name": "not an array"}`))
+	fmt.Println(err) // expected a JSON array, got {
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Custom JSON types
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Two APIs, two ideas of what a timestamp looks like.
+	var fromNewAPI, fromOldAPI Login
+	if err := json.Unmarshal([]byte(`{"user":"alice","at":"2024-03-01T12:30:00Z"}`), &fromNewAPI); err != nil {
+		log.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"user":"bob","at":1709296200}`), &fromOldAPI); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(fromNewAPI.At.Format(time.DateTime))     // 2024-03-01 12:30:00
+	fmt.Println(fromOldAPI.At.Format(time.DateTime))     // 2024-03-01 12:30:00, same moment
+	fmt.Println(fromNewAPI.At.Equal(fromOldAPI.At.Time)) // true
+
+	// Going out, it's always RFC3339.
+	loginJSON, err := json.Marshal(fromOldAPI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(loginJSON)) // {"user":"bob","at":"2024-03-01T12:30:00Z"}
+
+	// Round trip, back in and it's the same time.
+	var roundTrip Login
+	if err := json.Unmarshal(loginJSON, &roundTrip); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(roundTrip.At.Equal(fromOldAPI.At.Time)) // true
+
+	err = json.Unmarshal([]byte(`{"user":"eve","at":"last tuesday"}`), &roundTrip)
+	fmt.Println(err) // FlexTime: "last tuesday" is not RFC3339 or unix seconds
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// JSON with an unknown shape
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// No struct for this one, maybe it's a webhook from who knows where.
+	webhook := []byte(`{
+		"event": "signup",
+		"user": {"name": "Alice", "address": {"city": "Lisbon"}},
+		"tags": ["new", "trial"],
+		"attempts": 3
+	}`)
+
+	city, err := extractField(webhook, "user", "address", "city")
+	fmt.Println(city, err) // Lisbon <nil>
+
+	tag, err := extractField(webhook, "tags", "1") // array items by index
+	fmt.Println(tag, err)                          // trial <nil>
+
+	attempts, _ := extractField(webhook, "attempts")
+	fmt.Printf("%v %T\n", attempts, attempts) // 3 float64, ALL JSON numbers come out as float64!
+
+	_, err = extractField(webhook, "user", "email")
+	fmt.Println(err) // user.email: missing key "email"
+	_, err = extractField(webhook, "tags", "5")
+	fmt.Println(err) // tags.5: index 5 out of range, array has 2 items
+	_, err = extractField(webhook, "event", "name")
+	fmt.Println(err) // event.name: can't look up "name" in a string
+}
+
+// decodeUsers reads a JSON array of users one at a time.
+//
+// json.Unmarshal needs the whole thing in memory as a []byte first.
+// For a 10GB export that's 10GB of RAM (plus the decoded structs),
+// and your container gets OOM killed.
+//
+// json.Decoder reads from the io.Reader a chunk at a time:
+//   - dec.Token() reads one piece, like the opening [
+//   - dec.More() asks "is there another item before the ]?"
+//   - dec.Decode(&user) reads just ONE item
+//
+// So memory is one User at a time (plus whatever we keep). Here we keep them all in
+// a slice to keep the example short, a real job would process/save each and move on.
+//
+// Prefer streaming for big files, http bodies of unknown size, or
+// never-ending streams. For small payloads plain json.Unmarshal is simpler.
+func decodeUsers(r io.Reader) ([]User, error) {
+	dec := json.NewDecoder(r)
+
+	// The opening [
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected a JSON array, got %v", token)
+	}
+
+	var users []User
+	for dec.More() {
+		var user User
+		if err := dec.Decode(&user); err != nil {
+			return nil, fmt.Errorf("decoding user %d: %w", len(users), err)
+		}
+		users = append(users, user)
+	}
+
+	// The closing ]
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Login uses FlexTime like any other field type.
+type Login struct {
+	User string   `json:"user"`
+	At   FlexTime `json:"at"`
+}
+
+// FlexTime is a time.Time that accepts a timestamp as either an RFC3339
+// string, "2024-03-01T12:30:00Z", or a number of unix seconds, 1709296200.
+//
+// Embedding time.Time means a FlexTime has all of time.Time's methods
+// (Format, Before, Add...). That includes time.Time's own MarshalJSON and
+// UnmarshalJSON, which would only take RFC3339 strings, so we write ours.
+// Methods on the outer type win over promoted ones (see TimedSender in go_4).
+//
+// encoding/json checks every type for these two methods (the json.Marshaler
+// and json.Unmarshaler interfaces) and calls them instead of its defaults.
+// Python would need a custom JSONEncoder / object_hook for the same thing.
+type FlexTime struct {
+	time.Time
+}
+
+// UnmarshalJSON gets the raw JSON bytes for the field, quotes and all.
+// Pointer receiver, it has to change the FlexTime.
+func (t *FlexTime) UnmarshalJSON(data []byte) error {
+	raw := string(data)
+	if raw == "null" {
+		return nil // like encoding/json's own types, null means "leave it alone"
+	}
+
+	// A quoted string, try RFC3339.
+	if strings.HasPrefix(raw, `"`) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil { // takes care of escapes, like \"
+			return err
+		}
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("FlexTime: %q is not RFC3339 or unix seconds", s)
+		}
+		t.Time = parsed
+		return nil
+	}
+
+	// A bare number, unix seconds.
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("FlexTime: %s is not RFC3339 or unix seconds", raw)
+	}
+	t.Time = time.Unix(seconds, 0).UTC() // time.Unix gives local time, UTC so it prints the same everywhere
+	return nil
+}
+
+// MarshalJSON always writes RFC3339. Value receiver, so it works
+// on a FlexTime and a *FlexTime (go_22_methodsets.go).
+func (t FlexTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Format(time.RFC3339))
+}
+
+// extractField digs into JSON of unknown shape, following path one key
+// at a time, like python's data["user"]["address"]["city"].
+// Array items are looked up by their index as a string, "0", "1"...
+//
+// Unmarshal into an any and encoding/json picks Go types for you:
+//
+//	JSON object -> map[string]any
+//	JSON array  -> []any
+//	string      -> string
+//	number      -> float64 (even 3, use dec.UseNumber() if you need exact ints)
+//	true/false  -> bool
+//	null        -> nil
+//
+// Getting anything out takes a type assertion, x.(map[string]any).
+// The two value form, v, ok := x.(T), gives ok == false instead of panicking.
+//
+// Prefer a struct whenever you know the shape, this is for when you really don't.
+func extractField(raw []byte, path ...string) (any, error) {
+	var current any
+	if err := json.Unmarshal(raw, &current); err != nil {
+		return nil, err
+	}
+
+	for i, key := range path {
+		at := strings.Join(path[:i+1], ".") // where we are, for error messages
+
+		// A type switch, like a chain of v, ok := current.(T) checks.
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("%s: missing key %q", at, key)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q isn't an array index", at, key)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("%s: index %d out of range, array has %d items", at, index, len(node))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%s: can't look up %q in a %T", at, key, node)
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+//	go test go_7_json.go go_7_json_test.go
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeUsers(t *testing.T) {
+	input := `[
+		{"name": "Alice", "password": "Gopher123"},
+		{"name": "Bob", "password": "Gopher456"},
+		{"name": "Carol", "password": "Gopher789"}
+	]`
+	users, err := decodeUsers(strings.NewReader(input))
+	want := []User{{"Alice", "Gopher123"}, {"Bob", "Gopher456"}, {"Carol", "Gopher789"}}
+	if err != nil || !slices.Equal(users, want) {
+		t.Errorf("decodeUsers = %v, %v, want %v", users, err, want)
+	}
+
+	for name, bad := range map[string]string{
+		"not an array":   `{"name": "Alice"}`,
+		"bad item":       `[{"name": "Alice"}, {"name": 42}]`,
+		"never closed":   `[{"name": "Alice"}`,
+		"nothing at all": ``,
+	} {
+		if _, err := decodeUsers(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: err = nil, want an error", name)
+		}
+	}
+}
+
+func TestFlexTime(t *testing.T) {
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	for name, input := range map[string]string{
+		"RFC3339 string": `{"user": "alice", "at": "2024-03-01T12:30:00Z"}`,
+		"unix seconds":   `{"user": "alice", "at": 1709296200}`,
+	} {
+		var login Login
+		if err := json.Unmarshal([]byte(input), &login); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !login.At.Equal(want) {
+			t.Errorf("%s: At = %v, want %v", name, login.At, want)
+		}
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := json.Marshal(Login{User: "alice", At: FlexTime{want}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"user":"alice","at":"2024-03-01T12:30:00Z"}` {
+			t.Errorf("Marshal = %s", data)
+		}
+		var back Login
+		if err := json.Unmarshal(data, &back); err != nil || !back.At.Equal(want) {
+			t.Errorf("Unmarshal = %v, %v, want %v", back.At, err, want)
+		}
+	})
+
+	var login Login
+	if err := json.Unmarshal([]byte(`{"at": "next tuesday"}`), &login); err == nil {
+		t.Error("a made up date should fail")
+	}
+}
+
+func TestExtractField(t *testing.T) {
+	raw := []byte(`{"user": {"name": "Alice", "address": {"city": "Boulder"}, "tags": ["admin", "beta"], "age": 30}}`)
+
+	tests := []struct {
+		path    []string
+		want    any
+		wantErr string
+	}{
+		{[]string{"user", "address", "city"}, "Boulder", ""},
+		{[]string{"user", "tags", "1"}, "beta", ""},
+		{[]string{"user", "age"}, 30.0, ""}, // every JSON number is a float64
+		{[]string{"user", "phone"}, nil, `user.phone: missing key "phone"`},
+		{[]string{"user", "tags", "5"}, nil, "index 5 out of range"},
+		{[]string{"user", "tags", "first"}, nil, "isn't an array index"},
+		{[]string{"user", "name", "first"}, nil, "can't look up"},
+	}
+	for _, test := range tests {
+		got, err := extractField(raw, test.path...)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%v: err = %v, want %q", test.path, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%v = %v, %v, want %v", test.path, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User is the same struct from the intro, with the password stored in plain text 😬.
+// The xml tags are for the XML section, go_7_json.go explains tags.
+type User struct {
+	Name     string `xml:"name,attr"`
+	Password string `xml:"password"`
+}
+
+func main() {
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Hashing passwords (and hex encoding)
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// The intro's User keeps Password as plain text. If the db leaks, every
+	// password leaks, and people reuse passwords everywhere.
+	// Store a hash instead: a one-way fingerprint you can check, but not reverse.
+	aliceUser := User{Name: "Alice", Password: "Gopher123"}
+
+	stored := hashPassword(aliceUser.Password) // what goes in the db, instead of the password
+	salt, hash, _ := strings.Cut(stored, "$")
+	fmt.Println(len(salt), len(hash)) // 26 64, sha256 is 32 bytes, hex is 2 characters per byte
+
+	fmt.Println(checkPassword(stored, "Gopher123")) // true
+	fmt.Println(checkPassword(stored, "gopher123")) // false
+
+	// Every hash gets a new random salt, so two users with the password
+	// "Gopher123" don't have matching rows in the db. Both still check out.
+	bobStored := hashPassword("Gopher123")
+	fmt.Println(bobStored == stored, checkPassword(bobStored, "Gopher123")) // false true
+
+	// The slow, standard library way, see hashPasswordPBKDF2.
+	slowStored, err := hashPasswordPBKDF2(aliceUser.Password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.HasPrefix(slowStored, "pbkdf2-sha256$600000$")) // true
+	fmt.Println(checkPasswordPBKDF2(slowStored, "Gopher123"))           // true, after a noticeable pause
+	fmt.Println(checkPasswordPBKDF2(slowStored, "gopher123"))           // false
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Base64
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Some things only carry text: JSON strings, http headers, URLs, emails.
+	// Base64 turns ANY bytes (images, hashes, encrypted data) into plain A-Z a-z 0-9 + / text.
+	// python: base64.b64encode / JS: btoa
+	binaryMessage := append([]byte{0xff, 0xfe, 0x00, 0xfb}, "café 🍎"...) // raw bytes + multi-byte utf8
+
+	encoded := encodeMessage(binaryMessage)
+	fmt.Println(encoded) // //4A+2NhZsOpIPCfjY4=
+
+	decoded, err := decodeMessage(encoded)
+	fmt.Println(bytes.Equal(decoded, binaryMessage), err) // true <nil>
+
+	// The URL-safe version of the exact same bytes, no + / or =
+	urlEncoded := encodeMessageURL(binaryMessage)
+	fmt.Println(urlEncoded) // __4A-2NhZsOpIPCfjY4
+
+	urlDecoded, err := decodeMessageURL(urlEncoded)
+	fmt.Println(bytes.Equal(urlDecoded, binaryMessage), err) // true <nil>
+
+	// Empty in, empty out.
+	emptyDecoded, err := decodeMessage(encodeMessage([]byte{}))
+	fmt.Println(len(emptyDecoded), err) // 0 <nil>
+
+	// Not base64 at all, decoding fails instead of giving back garbage.
+	_, err = decodeMessage("not base64!")
+	fmt.Println(err) // illegal base64 data at input byte 3
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// XML
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// Same idea as JSON in go_7_json.go, Marshal/Unmarshal and struct tags.
+	// python: xml.etree.ElementTree, but there you build the tree by hand.
+	aliceXML, err := xml.MarshalIndent(aliceUser, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(aliceXML))
+	// <User name="Alice">
+	//   <password>Gopher123</password>
+	// </User>
+
+	// Name went in as an attribute, Password as a child element, because of the tags.
+	fmt.Println(strings.Contains(string(aliceXML), `<User name="Alice">`))            // true
+	fmt.Println(strings.Contains(string(aliceXML), `<password>Gopher123</password>`)) // true
+
+	var aliceAgain User
+	if err := xml.Unmarshal(aliceXML, &aliceAgain); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(aliceAgain == aliceUser) // true, round trip, structs of strings compare with ==
+
+	// How xml tags differ from json tags:
+	//
+	//	`json:"name"`        always a key, JSON has nothing else
+	//	`xml:"name"`         a child element, <name>Alice</name>
+	//	`xml:"name,attr"`    an attribute, <User name="Alice">
+	//	`xml:",chardata"`    the text between the tags, <User>Alice</User>
+	//	`xml:"a>b"`          nested elements, <a><b>Alice</b></a>, JSON would need nested structs
+	//	`xml:"-"`            skip it, same as `json:"-"`
+	//
+	// JSON has no root name, XML does. It's the type name (User) unless you
+	// add an XMLName xml.Name `xml:"user"` field to change it.
+	// In XML everything is text, Unmarshal parses it into whatever type the field is.
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// gob
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// gob is Go's own binary format, like python's pickle.
+	// Smaller and faster than JSON, but only Go can read it. Good for Go
+	// programs talking to Go programs (net/rpc uses it), or caching to disk.
+	// Anything a browser or a python service reads should stay JSON.
+	users := []User{{Name: "Alice", Password: "Gopher123"}, {Name: "Bob", Password: "Gopher456"}}
+
+	var buf bytes.Buffer // gob writes to any io.Writer, a file or a network connection work too
+	if err := gob.NewEncoder(&buf).Encode(users); err != nil {
+		log.Fatal(err)
+	}
+
+	var usersAgain []User
+	if err := gob.NewDecoder(&buf).Decode(&usersAgain); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(slices.Equal(users, usersAgain)) // true
+
+	// Interface fields. gob writes the concrete type's name into the stream,
+	// and the decoder has to turn that name back into a type. So both sides
+	// must gob.Register every concrete type that can end up in the interface.
+	gob.Register(EmailChannel{}) // usually in an init() func, once per program
+
+	var notificationBuf bytes.Buffer
+	notification := Notification{To: users[0], Via: EmailChannel{Address: "alice@example.com"}}
+	if err := gob.NewEncoder(&notificationBuf).Encode(notification); err != nil {
+		log.Fatal(err)
+	}
+	var notificationAgain Notification
+	if err := gob.NewDecoder(&notificationBuf).Decode(&notificationAgain); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(notificationAgain.Via.Describe())  // email to alice@example.com
+	fmt.Println(notificationAgain == notification) // true
+
+	// SMSChannel was never registered.
+	err = gob.NewEncoder(&bytes.Buffer{}).Encode(Notification{To: users[1], Via: SMSChannel{Number: "555-0100"}})
+	fmt.Println(err) // gob: type not registered for interface: main.SMSChannel
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// gzip
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// gzip.Writer is an io.Writer that compresses, gzip.Reader an io.Reader that
+	// decompresses. Put one between the JSON encoder and the file, and nothing
+	// else changes. Python's gzip.open(), but you stack the pieces yourself.
+	manyUsers := make([]User, 100)
+	for i := range manyUsers {
+		manyUsers[i] = User{Name: fmt.Sprintf("user%d", i), Password: "Gopher123"}
+	}
+
+	var gzipped bytes.Buffer
+	if err := writeUsersGzip(&gzipped, manyUsers); err != nil {
+		log.Fatal(err)
+	}
+	rawJSON, _ := json.Marshal(manyUsers)
+	fmt.Println(gzipped.Len() < len(rawJSON)/4, len(rawJSON)) // true 4091, JSON repeats itself a lot, it squashes well
+
+	manyUsersAgain, err := readUsersGzip(&gzipped)
+	fmt.Println(slices.Equal(manyUsers, manyUsersAgain), err) // true <nil>
+
+	// Forget Close, and the end of the data never gets written.
+	var unclosed bytes.Buffer
+	zipper := gzip.NewWriter(&unclosed)
+	json.NewEncoder(zipper).Encode(manyUsers)
+	_, err = readUsersGzip(&unclosed)
+	fmt.Println(err) // unexpected EOF
+
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// tar
+	// ******************************************************************************************************
+	// ******************************************************************************************************
+	// gzip squashes ONE stream of bytes. To bundle several files into one,
+	// that's tar, and a .tar.gz is a tar stream sent through gzip.
+	logs := []archiveFile{
+		{Name: "logs/alice.log", Body: "sent: hi bob\nsent: lunch?\n"},
+		{Name: "logs/bob.log", Body: "sent: hi alice\n"},
+		{Name: "logs/empty.log", Body: ""},
+	}
+
+	var tarred bytes.Buffer
+	if err := writeTar(&tarred, logs); err != nil {
+		log.Fatal(err)
+	}
+	logsAgain, err := readTar(&tarred)
+	fmt.Println(slices.Equal(logs, logsAgain), err) // true <nil>
+	for _, file := range logsAgain {
+		fmt.Printf("%s %q\n", file.Name, file.Body)
+	}
+	// logs/alice.log "sent: hi bob\nsent: lunch?\n"
+	// logs/bob.log "sent: hi alice\n"
+	// logs/empty.log ""
+}
+
+// archiveFile is a file for the tar section, in memory instead of on disk.
+type archiveFile struct {
+	Name string
+	Body string
+}
+
+// writeTar bundles files into a tar archive written to w.
+//
+// A tar is just files one after another, each one a header (name, size,
+// permissions, modified time...) then its bytes. No index at the front, so it
+// streams: writing never goes back, reading never jumps ahead. That's why
+// the header needs the Size BEFORE the body is written.
+//
+// For a .tar.gz, pass a gzip.Writer as w (and Close both, tar first).
+func writeTar(w io.Writer, files []archiveFile) error {
+	archive := tar.NewWriter(w)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    file.Name,
+			Mode:    0o644, // rw-r--r--, same as chmod 644
+			Size:    int64(len(file.Body)),
+			ModTime: time.Now(),
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(archive, file.Body); err != nil { // more bytes than Size is an error
+			return err
+		}
+	}
+	return archive.Close() // writes the end of archive marker, same story as gzip's Close
+}
+
+// readTar reads back every file in a tar archive.
+// Next moves to the next header, and the archive itself is a Reader
+// for that file's body, it stops at the end of the file.
+func readTar(r io.Reader) ([]archiveFile, error) {
+	archive := tar.NewReader(r)
+	var files []archiveFile
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return files, nil // no more files, not a failure
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+		// Extracting to disk? Check header.Name first, a "../../etc/passwd" name
+		// would write outside the folder ("zip slip"). filepath.IsLocal does the check.
+		files = append(files, archiveFile{Name: header.Name, Body: string(body)})
+	}
+}
+
+// writeUsersGzip writes users to w as gzipped JSON.
+//
+//	json.Encoder -> gzip.Writer -> w
+//
+// Close is NOT just cleanup here. gzip compresses in chunks and holds the
+// last one back, Close writes it, plus the footer (a checksum and the size).
+// Without it the file is cut off, and readers fail with unexpected EOF.
+// That's why its error gets returned, not ignored by a defer.
+func writeUsersGzip(w io.Writer, users []User) error {
+	zipper := gzip.NewWriter(w)
+	if err := json.NewEncoder(zipper).Encode(users); err != nil {
+		zipper.Close()
+		return err
+	}
+	return zipper.Close() // doesn't close w, just finishes the gzip data
+}
+
+// readUsersGzip reads what writeUsersGzip wrote.
+//
+//	r -> gzip.Reader -> json.Decoder
+func readUsersGzip(r io.Reader) ([]User, error) {
+	unzipper, err := gzip.NewReader(r) // reads the header right away, so data that isn't gzip fails here
+	if err != nil {
+		return nil, err
+	}
+	defer unzipper.Close()
+
+	var users []User
+	if err := json.NewDecoder(unzipper).Decode(&users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Channel is how a notification gets delivered, for the gob section.
+type Channel interface {
+	Describe() string
+}
+
+type EmailChannel struct {
+	Address string
+}
+
+func (c EmailChannel) Describe() string { return "email to " + c.Address }
+
+type SMSChannel struct {
+	Number string
+}
+
+func (c SMSChannel) Describe() string { return "sms to " + c.Number }
+
+// Notification has an interface field, gob needs the concrete types registered.
+type Notification struct {
+	To  User
+	Via Channel
+}
+
+// hashPassword hashes pw with a new random salt, and returns "salt$hash",
+// the salt has to be kept to check the password later, so it's stored right in the result.
+//
+// The salt is crypto/rand's Text, 26 random characters. crypto/rand, NOT
+// math/rand! math/rand is predictable, fine for picking stock symbols in
+// go_3_goroutines.go, terrible for security.
+//
+// A hash is raw bytes, like 0x9f 0x86 0xd0... which can't go in a string or
+// a db text column safely. hex.EncodeToString writes each byte as two
+// characters 0-9a-f, so "9f86d0...". python: hashlib.sha256(b).hexdigest()
+//
+// ⚠️ This is still NOT how to store passwords for real. sha256 is designed to
+// be FAST, so an attacker with a GPU can try billions of guesses a second.
+// Use a deliberately slow password hash, bcrypt (or scrypt/argon2), see below.
+func hashPassword(pw string) string {
+	salt := rand.Text()
+	return salt + "$" + sha256Hex(salt, pw)
+}
+
+func sha256Hex(salt, pw string) string {
+	sum := sha256.Sum256([]byte(salt + pw))
+	return hex.EncodeToString(sum[:]) // sum is a [32]byte array, [:] makes it a slice
+}
+
+// checkPassword hashes pw with stored's salt, and compares with stored's hash.
+//
+// subtle.ConstantTimeCompare takes the same time whether the first or last
+// character differs. A normal == stops at the first difference, and attackers
+// can time that to guess the hash a character at a time.
+func checkPassword(stored, pw string) bool {
+	salt, hash, ok := strings.Cut(stored, "$")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(sha256Hex(salt, pw))) == 1
+}
+
+// pbkdf2Iterations is how many times PBKDF2 runs the hash, OWASP's advice for sha256.
+// More is slower for attackers AND for us, raise it as computers get faster.
+const pbkdf2Iterations = 600_000
+
+// hashPasswordPBKDF2 is the real answer with only the standard library.
+//
+// PBKDF2 runs the hash hundreds of thousands of times in a row, so one guess
+// costs an attacker ~100ms instead of nanoseconds. It stores everything
+// needed to check it later, "pbkdf2-sha256$iterations$salt$hash", so the
+// iterations can go up for new passwords without breaking the old ones.
+// python: hashlib.pbkdf2_hmac("sha256", pw, salt, 600_000)
+func hashPasswordPBKDF2(pw string) (string, error) {
+	salt := rand.Text()
+	key, err := pbkdf2.Key(sha256.New, pw, []byte(salt), pbkdf2Iterations, 32)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%x", pbkdf2Iterations, salt, key), nil
+}
+
+// checkPasswordPBKDF2 redoes hashPasswordPBKDF2 with stored's settings, and compares.
+func checkPasswordPBKDF2(stored, pw string) bool {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	key, err := pbkdf2.Key(sha256.New, pw, []byte(parts[2]), iterations, 32)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(parts[3]), []byte(hex.EncodeToString(key))) == 1
+}
+
+// bcrypt
+//
+// bcrypt lives in golang.org/x/crypto, maintained by the Go team but outside the
+// standard library. These tutorial files stick to the standard library so they
+// run without a go.mod, so hashPasswordPBKDF2 above is the one that runs.
+// bcrypt is the more common pick, here it is for your own project (after go get golang.org/x/crypto/bcrypt):
+//
+//	import "golang.org/x/crypto/bcrypt"
+//
+//	func hashPasswordBcrypt(pw string) (string, error) {
+//	  // bcrypt makes its own salt and stores it inside the hash string,
+//	  // DefaultCost (10) is how slow it is, go up as computers get faster.
+//	  hash, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+//	  return string(hash), err
+//	}
+//
+//	func checkPasswordBcrypt(hash, pw string) bool {
+//	  return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pw)) == nil
+//	}
+
+// encodeMessage turns bytes into standard base64 text.
+//
+// Base64 writes every 3 bytes as 4 characters (so it's ~33% bigger).
+// When the input isn't a multiple of 3 bytes, "=" padding fills out the last 4:
+//
+//	"a"   -> "YQ=="
+//	"ab"  -> "YWI="
+//	"abc" -> "YWJj"
+func encodeMessage(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// decodeMessage turns standard base64 text back into bytes.
+func decodeMessage(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// encodeMessageURL uses the URL-safe alphabet.
+//
+// Standard base64 uses + and /, which mean something in URLs and file paths
+// (/ is a folder, + is a space in query strings), and = is the query key=value separator.
+// URL encoding swaps in - and _, and RawURLEncoding drops the = padding too.
+// Pick it for anything going in a URL, a filename, or a cookie (JWTs use it).
+func encodeMessageURL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeMessageURL turns URL-safe, unpadded base64 back into bytes.
+func decodeMessageURL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
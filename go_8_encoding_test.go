@@ -0,0 +1,166 @@
+package main
+
+//	go test go_8_encoding.go go_8_encoding_test.go
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	first, second := hashPassword("Gopher123"), hashPassword("Gopher123")
+	if first == second {
+		t.Error("two hashes of the same password are equal, the salts should differ")
+	}
+	for _, stored := range []string{first, second} {
+		if !checkPassword(stored, "Gopher123") {
+			t.Errorf("checkPassword(%q, right password) = false", stored)
+		}
+		if checkPassword(stored, "gopher123") {
+			t.Errorf("checkPassword(%q, wrong password) = true", stored)
+		}
+	}
+
+	// Same salt and password, same hash, that's what makes checking possible.
+	if sha256Hex("salt", "pw") != sha256Hex("salt", "pw") || sha256Hex("salt", "pw") == sha256Hex("pepper", "pw") {
+		t.Error("sha256Hex should depend on the salt, and only on its inputs")
+	}
+	if checkPassword("no dollar sign", "Gopher123") {
+		t.Error("a malformed stored hash should never match")
+	}
+}
+
+func TestHashPasswordPBKDF2(t *testing.T) {
+	stored, err := hashPasswordPBKDF2("Gopher123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored, "pbkdf2-sha256$600000$") {
+		t.Errorf("stored = %q, want the algorithm and iterations up front", stored)
+	}
+	if !checkPasswordPBKDF2(stored, "Gopher123") || checkPasswordPBKDF2(stored, "hunter2") {
+		t.Error("checkPasswordPBKDF2 should accept only the right password")
+	}
+	for _, bad := range []string{"", "pbkdf2-sha256$lots$salt$key", "bcrypt$1$2$3"} {
+		if checkPasswordPBKDF2(bad, "Gopher123") {
+			t.Errorf("checkPasswordPBKDF2(%q) = true, want false", bad)
+		}
+	}
+}
+
+func TestBase64RoundTrip(t *testing.T) {
+	inputs := map[string][]byte{
+		"empty":      {},
+		"binary":     {0x00, 0xff, 0xfe, '+', '/', '='},
+		"multi-byte": []byte("AAPL 📈 über"),
+		"one byte":   []byte("a"), // needs padding
+	}
+	for name, input := range inputs {
+		decoded, err := decodeMessage(encodeMessage(input))
+		if err != nil || !bytes.Equal(decoded, input) {
+			t.Errorf("%s: std round trip = %v, %v, want %v", name, decoded, err, input)
+		}
+
+		urlSafe := encodeMessageURL(input)
+		if strings.ContainsAny(urlSafe, "+/=") {
+			t.Errorf("%s: %q has characters that aren't URL safe", name, urlSafe)
+		}
+		decoded, err = decodeMessageURL(urlSafe)
+		if err != nil || !bytes.Equal(decoded, input) {
+			t.Errorf("%s: url round trip = %v, %v, want %v", name, decoded, err, input)
+		}
+	}
+
+	if _, err := decodeMessage("not base64!"); err == nil {
+		t.Error("decodeMessage(garbage) = nil error")
+	}
+}
+
+func TestUserXML(t *testing.T) {
+	alice := User{Name: "Alice", Password: "Gopher123"}
+	data, err := xml.Marshal(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Name is an attribute, Password a child element.
+	if want := `<User name="Alice"><password>Gopher123</password></User>`; string(data) != want {
+		t.Errorf("xml = %s, want %s", data, want)
+	}
+
+	var back User
+	if err := xml.Unmarshal(data, &back); err != nil || back != alice {
+		t.Errorf("round trip = %v, %v, want %v", back, err, alice)
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	users := []User{{Name: "Alice", Password: "Gopher123"}, {Name: "Bob", Password: "Gopher456"}}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(users); err != nil {
+		t.Fatal(err)
+	}
+	var back []User
+	if err := gob.NewDecoder(&buf).Decode(&back); err != nil || !slices.Equal(back, users) {
+		t.Errorf("round trip = %v, %v, want %v", back, err, users)
+	}
+
+	gob.Register(EmailChannel{}) // main registers it too, but main doesn't run in tests
+	notification := Notification{To: users[0], Via: EmailChannel{Address: "alice@example.com"}}
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(notification); err != nil {
+		t.Fatal(err)
+	}
+	var notificationBack Notification
+	if err := gob.NewDecoder(&buf).Decode(&notificationBack); err != nil || notificationBack != notification {
+		t.Errorf("round trip = %v, %v, want %v", notificationBack, err, notification)
+	}
+}
+
+func TestUsersGzip(t *testing.T) {
+	var users []User
+	for i := 0; i < 100; i++ {
+		users = append(users, User{Name: "user", Password: "Gopher123"}) // repetitive, like real JSON
+	}
+	raw, err := json.Marshal(users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	if err := writeUsersGzip(&compressed, users); err != nil {
+		t.Fatal(err)
+	}
+	if compressed.Len() >= len(raw) {
+		t.Errorf("gzipped %d bytes, raw JSON is %d, want it smaller", compressed.Len(), len(raw))
+	}
+
+	back, err := readUsersGzip(&compressed)
+	if err != nil || !slices.Equal(back, users) {
+		t.Errorf("round trip = %d users, %v, want %d", len(back), err, len(users))
+	}
+
+	if _, err := readUsersGzip(strings.NewReader("not gzip")); err == nil {
+		t.Error("readUsersGzip(not gzip) = nil error")
+	}
+}
+
+func TestTarRoundTrip(t *testing.T) {
+	files := []archiveFile{
+		{Name: "logs/alice.log", Body: "sent: hi bob\n"},
+		{Name: "logs/bob.log", Body: "sent: hi alice\n"},
+		{Name: "logs/empty.log", Body: ""},
+	}
+	var archive bytes.Buffer
+	if err := writeTar(&archive, files); err != nil {
+		t.Fatal(err)
+	}
+	back, err := readTar(&archive)
+	if err != nil || !slices.Equal(back, files) {
+		t.Errorf("readTar = %v, %v, want %v", back, err, files)
+	}
+}
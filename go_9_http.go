@@ -0,0 +1,764 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode"
+)
+
+// User is the same struct from the intro, with json tags (go_7_json.go).
+type User struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func main() {
+	// The standard library has a production grade http server built in,
+	// no flask/express needed.
+	//
+	// A "handler" is anything with a ServeHTTP(w, r) method (http.Handler interface),
+	// or a plain func(w, r) wrapped in http.HandlerFunc.
+	//   r is the request coming in (method, url, headers, body)
+	//   w is where we write the response (status, headers, body)
+	handler := newRouter()
+
+	// httptest starts a real server on a random free port, handy to try
+	// things out without opening a browser. (It's normally used in tests.)
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	fmt.Println(get(testServer.URL + "/hello")) // 200 hello!
+
+	// A handler that panics gets a 500, and the server keeps running.
+	fmt.Println(get(testServer.URL + "/panic")) // 500 Internal Server Error  (and the stack in the logs)
+	fmt.Println(get(testServer.URL + "/hello")) // 200 hello!, still up
+
+	// Every request gets an ID, in the logs and available to handlers.
+	fmt.Println(get(testServer.URL + "/whoami")) // 200 you are request 3f2a9c1e-... (same ID as its log line)
+
+	// A JSON API, POST a user.
+	fmt.Println(post(testServer.URL+"/users", `{"name": "alice", "password": "Gopher123"}`))
+	// 201 {"name":"alice","password":"****"}
+	fmt.Println(post(testServer.URL+"/users", `{"name": "", "password": "short"}`))
+	// 422 {"errors":{"name":"is required","password":"must be at least 8 characters"}}
+	fmt.Println(post(testServer.URL+"/users", `{"name": "bob", "password": "Gopher456", "admin": true}`))
+	// 400 {"errors":{"body":"json: unknown field \"admin\""}}, nice try
+	fmt.Println(post(testServer.URL+"/users", `{"name": "bob"`))
+	// 400 {"errors":{"body":"unexpected EOF"}}
+
+	// Metrics for everything so far, in the format Prometheus scrapes.
+	_, metricsText, _ := strings.Cut(get(testServer.URL+"/metrics"), " ") // drop the "200 "
+	for _, line := range strings.Split(metricsText, "\n") {
+		if strings.HasPrefix(line, "http_requests_total") || strings.HasPrefix(line, "http_request_errors_total") {
+			fmt.Println(line)
+		}
+	}
+	// http_requests_total 8        hello, panic, hello, whoami, the 4 user POSTs (this /metrics call isn't done yet)
+	// http_request_errors_total 1  the panic
+
+	// Server-sent events, read 3 then hang up.
+	events, err := readEvents(testServer.URL+"/stocks?every=100ms", 3)
+	fmt.Println(events, err) // [🍎 AAPL 🤓 GOOG 🤢 FB] <nil>, and the log says the client left
+
+	// ?every comes from the client, so check it. time.NewTicker panics on 0 or less.
+	fmt.Print(get(testServer.URL + "/stocks?every=0s")) // 400 every must be a positive duration, like 500ms
+
+	// Which order do chained middleware run in? Each one notes when it runs.
+	var calls []string
+	traced := Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") }),
+		traceMiddleware("first", &calls),
+		traceMiddleware("second", &calls),
+		traceMiddleware("third", &calls),
+	)
+	// No server needed, a handler is just something we can call.
+	traced.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	fmt.Println(calls) // [first second third handler third second first]
+
+	// Shutting down without cutting off a request halfway through.
+	status, err := demoGracefulShutdown()
+	fmt.Println(status, err) // 200 slow but done <nil>, the request finished before the server stopped
+
+	// For real now, try curl localhost:8080/hello
+	// Ctrl+C (SIGINT), or SIGTERM from docker/kubernetes, shuts it down gracefully.
+	server := newServer(":8080", handler)
+
+	// ctx is cancelled when one of those signals arrives.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Println("listening on http://localhost:8080")
+		// ListenAndServe always returns an error. ErrServerClosed is the good one, it means Shutdown was called.
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("shutting down, finishing requests in flight")
+	if err := shutdown(server, 10*time.Second); err != nil {
+		log.Println("some requests didn't finish in time:", err)
+	}
+}
+
+// newServer makes an http.Server with timeouts.
+//
+// http.ListenAndServe(addr, handler) is fine for trying things out, but
+// its server has NO timeouts at all. Every connection costs memory and a
+// goroutine, so a client that connects and then sends one byte a minute
+// (a "slowloris" attack), or a lot of sleepy phones on bad connections,
+// can hold connections open forever until the server runs out.
+//
+//	ReadHeaderTimeout  how long a client gets to send the request headers
+//	ReadTimeout        ...to send the whole request, headers and body
+//	WriteTimeout       how long a handler + writing the response gets, counted from the end of the headers
+//	IdleTimeout        how long a kept alive connection can sit between requests
+func newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second, // longer than your slowest handler!
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// shutdown stops server gracefully, waiting up to deadline.
+//
+// Shutdown closes the listener first (no new connections), then waits for
+// requests in flight to finish ("draining"), closing connections as they go idle.
+// If the deadline hits first it gives up and returns the context's error,
+// call server.Close() after that if you need everything dead right now.
+func shutdown(server *http.Server, deadline time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// demoGracefulShutdown starts a server, makes a slow request, and shuts
+// down while the request is still running. It returns what the client got,
+// and what Shutdown returned.
+func demoGracefulShutdown() (string, error) {
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "slow but done")
+	})
+	server := newServer("", mux)
+
+	// Port 0 means "any free port", the listener tells us which one we got.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	go func() { _ = server.Serve(listener) }() // returns ErrServerClosed after Shutdown
+
+	response := make(chan string)
+	go func() {
+		response <- get("http://" + listener.Addr().String() + "/slow")
+	}()
+
+	<-started // the request is in the handler now
+	shutdownErr := shutdown(server, 2*time.Second)
+	return <-response, shutdownErr
+}
+
+// newRouter puts all the routes together.
+//
+// A "mux" (multiplexer) picks a handler by the url path, like flask's @app.route.
+// Since Go 1.22 patterns can include the method too, "GET /hello".
+func newRouter() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /hello", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello!") // w is an io.Writer, anything that writes can write to it
+	})
+
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		var users map[string]string
+		users["oops"] = "nil map write" // panics, writing to a nil map (never made with make)
+	})
+
+	mux.HandleFunc("POST /users", createUserHandler)
+
+	mux.HandleFunc("GET /stocks", stockEventsHandler)
+
+	mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "you are request %s", requestIDFromContext(r.Context()))
+	})
+
+	metrics := &httpMetrics{}
+	mux.Handle("GET /metrics", metrics) // *httpMetrics has a ServeHTTP method, so it IS a handler
+
+	// Wrap the whole mux, so every route is covered.
+	// Logging and metrics go outside recover, so they see the 500 it writes.
+	return Chain(mux, requestLogMiddleware, metrics.Middleware, recoverMiddleware)
+}
+
+// Chain wraps h in middleware, first one listed is the outermost.
+//
+//	Chain(mux, requestLogMiddleware, recoverMiddleware)
+//
+// is the same as requestLogMiddleware(recoverMiddleware(mux)), but reads
+// in the order a request goes through:
+//
+//	request -> requestLog -> recover -> mux
+//	                                     |
+//	response <- requestLog <- recover <--+
+//
+// Each middleware's "before" code runs in list order, its "after" code
+// (anything after next.ServeHTTP) in reverse order, like nested python decorators.
+func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
+	// Wrap from the inside out, the last middleware goes on first.
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// createUserHandler reads a User from the JSON body, and validates it.
+//
+//	201 Created               the user, password redacted (never send a password back!)
+//	400 Bad Request           the body isn't valid JSON, or has fields we don't know
+//	422 Unprocessable Entity  valid JSON, but the values are wrong, one message per field
+//
+// Like a flask view doing request.get_json() plus a marshmallow/pydantic schema.
+func createUserHandler(w http.ResponseWriter, r *http.Request) {
+	// Don't let anyone send us a 10GB body, 1MB is plenty for a user.
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	dec := json.NewDecoder(r.Body)
+	// By default unknown fields are silently ignored. A typo like "pasword"
+	// would give a user with no password, and no error. Make it an error.
+	dec.DisallowUnknownFields()
+
+	var user User
+	if err := dec.Decode(&user); err != nil {
+		writeJSON(w, http.StatusBadRequest, fieldErrors{"body": err.Error()})
+		return
+	}
+
+	if errs := validateUser(user); len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, errs)
+		return
+	}
+
+	// A real API would save the user (with a hashed password, go_8_encoding.go) here.
+	user.Password = "****"
+	writeJSON(w, http.StatusCreated, user)
+}
+
+// fieldErrors maps a field name to what's wrong with it.
+// MarshalJSON nests it under "errors", {"errors": {"password": "..."}}.
+type fieldErrors map[string]string
+
+func (f fieldErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]map[string]string{"errors": f}) // map keys come out sorted
+}
+
+// validateUser checks every field, and reports ALL the problems at once,
+// so a form can highlight every bad field instead of one per submit.
+func validateUser(user User) fieldErrors {
+	errs := fieldErrors{}
+	if strings.TrimSpace(user.Name) == "" {
+		errs["name"] = "is required"
+	}
+	if err := validatePassword(user.Password); err != nil {
+		errs["password"] = err.Error()
+	}
+	return errs
+}
+
+// validatePassword wants 8+ characters, with an upper case letter and a digit.
+func validatePassword(password string) error {
+	if len([]rune(password)) < 8 { // count characters, not bytes (go_19_runes.go)
+		return errors.New("must be at least 8 characters")
+	}
+	var hasUpper, hasDigit bool
+	for _, r := range password {
+		hasUpper = hasUpper || unicode.IsUpper(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasUpper {
+		return errors.New("must have an upper case letter")
+	}
+	if !hasDigit {
+		return errors.New("must have a digit")
+	}
+	return nil
+}
+
+// writeJSON sends v as JSON with the given status.
+// Headers have to be set BEFORE WriteHeader, after that they're already sent.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Print(err) // too late to change the status, just log it
+	}
+}
+
+// minEventInterval is the fastest a client can ask /stocks to send.
+const minEventInterval = 100 * time.Millisecond
+
+// stockEventsHandler streams the stock emojis from go_3_goroutines.go
+// to the browser, one every second (or ?every=500ms), until the client leaves.
+//
+// It's "server-sent events" (SSE), the simple way to push updates to a browser:
+//
+//	const events = new EventSource("/stocks")
+//	events.onmessage = (e) => console.log(e.data)
+//
+// The response just never ends. Each event is "data: ...", then a blank line.
+// Needs the text/event-stream Content-Type, or EventSource won't listen.
+//
+// Normally the server buffers what you write and sends it in chunks, an event
+// could sit in the buffer for ages. Flushing pushes it out right away.
+//
+// Flush isn't part of http.ResponseWriter, it's the separate http.Flusher
+// interface. The classic way is a type assertion, w.(http.Flusher), but that
+// fails here! Our w is requestLogMiddleware's statusRecorder, which only
+// embeds the ResponseWriter interface, so it only has ResponseWriter's methods.
+// http.NewResponseController (Go 1.20) digs through wrappers with an Unwrap
+// method to find the real writer's Flush.
+func stockEventsHandler(w http.ResponseWriter, r *http.Request) {
+	interval := time.Second
+	if every := r.URL.Query().Get("every"); every != "" {
+		parsed, err := time.ParseDuration(every)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "every must be a positive duration, like 500ms", http.StatusBadRequest)
+			return
+		}
+		// ?every=1ns would have us writing events as fast as the CPU allows, to anyone who asks.
+		interval = max(parsed, minEventInterval)
+	}
+
+	flusher := http.NewResponseController(w)
+
+	// newServer's WriteTimeout (30s) is a limit on the WHOLE response, fine for
+	// a page, but it would cut every stream off at 30s with no error anywhere.
+	// Streaming routes turn it off, just for this request. The zero time.Time is "no deadline".
+	// (httptest.NewRecorder can't do deadlines, that's ErrNotSupported, fine to ignore.)
+	_ = flusher.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache") // don't let a proxy hold onto it
+
+	stocks := []struct{ symbol, icon string }{{"AAPL", "🍎"}, {"GOOG", "🤓"}, {"FB", "🤢"}, {"AMZN", "📦"}}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		stock := stocks[i%len(stocks)]
+		// Plain fmt.Fprintf, an event is just text.
+		fmt.Fprintf(w, "id: %d\ndata: %s %s\n\n", i, stock.icon, stock.symbol)
+		if err := flusher.Flush(); err != nil {
+			log.Print(err) // this writer can't stream, no point carrying on
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			// The request's context is cancelled when the client hangs up (or the server shuts down).
+			// Without this, we'd write into the void forever, a leaked goroutine per visitor.
+			log.Printf("%s left after %d events", requestIDFromContext(r.Context()), i+1)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readEvents reads n events from an SSE url, then cancels the request.
+func readEvents(url string, n int) ([]string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // hangs up, the handler sees r.Context().Done()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body) // line by line, as they arrive
+	for len(events) < n && scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// httpMetrics counts requests, 5xx errors, and a latency histogram.
+//
+// It's a copy of MetricsSender in go_4_structs_interfaces.go, same atomics,
+// same buckets, same Snapshot/LatencyBucket names, with "sends" renamed to
+// "requests". A copy, not an import, because every go_*.go here is its own
+// `package main` program, and one main package can't import another.
+// In a real project both would use one small package, say internal/metrics,
+// with a Histogram that MetricsSender and this middleware both record into.
+type httpMetrics struct {
+	requests     atomic.Int64
+	errors       atomic.Int64
+	totalLatency atomic.Int64                          // nanoseconds, for the average
+	buckets      [len(latencyBuckets) + 1]atomic.Int64 // one per bound, plus "slower than all of them"
+}
+
+// latencyBuckets are the upper bounds of the histogram buckets.
+var latencyBuckets = [...]time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+
+// Middleware times every request, and counts the ones answered with a 5xx.
+func (m *httpMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		took := time.Since(start)
+
+		m.requests.Add(1)
+		if recorder.status >= 500 {
+			m.errors.Add(1)
+		}
+		m.totalLatency.Add(int64(took))
+
+		bucket := len(latencyBuckets) // the catch all
+		for i, bound := range latencyBuckets {
+			if took <= bound {
+				bucket = i
+				break
+			}
+		}
+		m.buckets[bucket].Add(1)
+	})
+}
+
+// MetricsSnapshot is a copy of the numbers at one moment, plain values, safe to hand around.
+type MetricsSnapshot struct {
+	Requests     int64
+	Errors       int64
+	TotalLatency time.Duration
+	Buckets      []LatencyBucket
+}
+
+// LatencyBucket counts the requests that took more than the previous bucket's UpTo,
+// and at most UpTo. The last bucket's UpTo is 0, meaning "slower than everything else".
+type LatencyBucket struct {
+	UpTo  time.Duration
+	Count int64
+}
+
+// Snapshot reads all the counters, each one atomically but not all together,
+// same as MetricsSender.Snapshot.
+func (m *httpMetrics) Snapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		Requests:     m.requests.Load(),
+		Errors:       m.errors.Load(),
+		TotalLatency: time.Duration(m.totalLatency.Load()),
+	}
+	for i := range m.buckets {
+		var upTo time.Duration // 0 for the catch all
+		if i < len(latencyBuckets) {
+			upTo = latencyBuckets[i]
+		}
+		snapshot.Buckets = append(snapshot.Buckets, LatencyBucket{UpTo: upTo, Count: m.buckets[i].Load()})
+	}
+	return snapshot
+}
+
+// ServeHTTP writes the metrics in Prometheus' text format. No client library
+// needed, it's plain text, a Prometheus server just GETs it every 15s or so:
+//
+//	# HELP http_requests_total Requests handled.      <-- a description, for humans
+//	# TYPE http_requests_total counter                <-- counter, gauge, histogram or summary
+//	http_requests_total 8                             <-- name value
+//
+// Counters only ever go up, Prometheus works out the rate itself.
+// A histogram is several lines. The buckets are "cumulative", each le (less or
+// equal) bucket counts everything up to that bound, so the +Inf one is every request:
+//
+//	http_request_duration_seconds_bucket{le="0.001"} 7
+//	http_request_duration_seconds_bucket{le="0.01"} 8
+//	http_request_duration_seconds_bucket{le="+Inf"} 8
+//	http_request_duration_seconds_sum 0.0031
+//	http_request_duration_seconds_count 8
+//
+// Durations are always in seconds, names end in their unit (_seconds, _bytes, _total).
+func (m *httpMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := m.Snapshot()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP http_requests_total Requests handled.")
+	fmt.Fprintln(w, "# TYPE http_requests_total counter")
+	fmt.Fprintln(w, "http_requests_total", snapshot.Requests)
+
+	fmt.Fprintln(w, "# HELP http_request_errors_total Requests answered with a 5xx status.")
+	fmt.Fprintln(w, "# TYPE http_request_errors_total counter")
+	fmt.Fprintln(w, "http_request_errors_total", snapshot.Errors)
+
+	fmt.Fprintln(w, "# HELP http_request_duration_seconds How long requests took.")
+	fmt.Fprintln(w, "# TYPE http_request_duration_seconds histogram")
+	var cumulative int64
+	for _, bucket := range snapshot.Buckets {
+		cumulative += bucket.Count // our buckets aren't cumulative, Prometheus' are
+		le := "+Inf"
+		if bucket.UpTo > 0 {
+			le = fmt.Sprint(bucket.UpTo.Seconds())
+		}
+		fmt.Fprintf(w, "http_request_duration_seconds_bucket{le=%q} %d\n", le, cumulative)
+	}
+	fmt.Fprintln(w, "http_request_duration_seconds_sum", snapshot.TotalLatency.Seconds())
+	fmt.Fprintln(w, "http_request_duration_seconds_count", cumulative)
+}
+
+// WebSockets
+//
+// SSE above only goes one way, server to browser. A WebSocket is a two way
+// connection: it starts as a normal http request, then both sides "upgrade"
+// it and send messages back and forth whenever they like. Chat, games, live editing.
+//
+// net/http doesn't do WebSockets, you'd use github.com/gorilla/websocket
+// (or github.com/coder/websocket, which used to be nhooyr.io/websocket). Like bcrypt in go_8_encoding.go
+// it's outside the standard library, and these files run without a go.mod,
+// so here it is for your own project (after go get github.com/gorilla/websocket).
+//
+// An echo handler, that also passes every message on to a SenderInterface (go_4):
+//
+//	import "github.com/gorilla/websocket"
+//
+//	var upgrader = websocket.Upgrader{
+//	  // Browsers will connect from any site that has your URL, check where they come from!
+//	  CheckOrigin: func(r *http.Request) bool { return r.Header.Get("Origin") == "https://example.com" },
+//	}
+//
+//	func echoHandler(sender SenderInterface) http.HandlerFunc {
+//	  return func(w http.ResponseWriter, r *http.Request) {
+//	    // Upgrade takes over the raw connection (http.Hijacker). Like Flush in
+//	    // stockEventsHandler, middleware wrapping w has to let it through.
+//	    conn, err := upgrader.Upgrade(w, r, nil) // writes the 101 Switching Protocols response
+//	    if err != nil {
+//	      return // Upgrade already sent the client an error
+//	    }
+//	    defer conn.Close()
+//
+//	    // Ping/pong: we ping every 30s, the client's browser pongs back automatically.
+//	    // No pong for 60s? It's gone (laptop lid closed, wifi dropped), ReadMessage errors out.
+//	    conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+//	    conn.SetPongHandler(func(string) error {
+//	      return conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+//	    })
+//	    go func() {
+//	      ticker := time.NewTicker(30 * time.Second)
+//	      defer ticker.Stop()
+//	      for range ticker.C {
+//	        // WriteControl is the one write that's safe alongside other writes.
+//	        if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+//	          return // connection closed
+//	        }
+//	      }
+//	    }()
+//
+//	    for {
+//	      kind, message, err := conn.ReadMessage()
+//	      if err != nil {
+//	        // A clean close (the client called ws.close()) is expected, anything else is worth a log.
+//	        if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+//	          log.Println("websocket:", err)
+//	        }
+//	        return
+//	      }
+//	      if err := sender.Send(string(message)); err != nil {
+//	        log.Println("forwarding:", err)
+//	      }
+//	      if err := conn.WriteMessage(kind, message); err != nil { // echo it back
+//	        return
+//	      }
+//	    }
+//	  }
+//	}
+//
+// And trying it out, gorilla's Dialer is the client side:
+//
+//	server := httptest.NewServer(echoHandler(&FlakySender{}))
+//	defer server.Close()
+//	url := "ws" + strings.TrimPrefix(server.URL, "http") // http://127.0.0.1:1234 -> ws://127.0.0.1:1234
+//	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+//	...
+//	conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+//	_, echo, err := conn.ReadMessage() // echo is "hello"
+//
+//	// Clean close: say goodbye with a close message, the server's ReadMessage gets a CloseNormalClosure.
+//	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye"))
+//
+// From a browser it's built in, no library:
+//
+//	const ws = new WebSocket("ws://localhost:8080/echo")
+//	ws.onmessage = (e) => console.log(e.data)
+//	ws.send("hello")
+
+// recoverMiddleware catches panics from any handler inside it, and
+// answers 500 instead.
+//
+// "Middleware" is a handler that wraps another handler, doing something
+// before and/or after it, like python decorators or express app.use().
+//
+//	func(next http.Handler) http.Handler
+//
+// net/http already recovers panics per request (so one bad request doesn't
+// kill the server), but it just drops the connection, so the client gets
+// an EOF instead of a response. Doing it ourselves means a proper 500,
+// and our own log format for the stack trace.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			// recover() only works inside a deferred func. It returns nil
+			// when there was no panic, otherwise whatever was passed to panic().
+			if recovered := recover(); recovered != nil {
+				// http.ErrAbortHandler is the one panic that's on purpose, a handler's
+				// way of saying "drop this connection, no response". The server
+				// knows it and doesn't log it, so hand it back instead of writing a 500.
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				log.Printf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, recovered, debug.Stack())
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(w, r) // run the real handler
+	})
+}
+
+// traceMiddleware adds its name to calls before and after the handler runs.
+// Returning a middleware from a func is how middleware take settings.
+func traceMiddleware(name string, calls *[]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next.ServeHTTP(w, r)
+			*calls = append(*calls, name)
+		})
+	}
+}
+
+// ctxKey is our own private type for context keys, so no other
+// package can accidentally read or overwrite our values (see go_10_context.go).
+type ctxKey int
+
+const requestIDKey ctxKey = iota
+
+// requestLogMiddleware gives each request an ID, and logs one line per request:
+//
+//	3f2a9c1e-... GET /hello 200 41.2µs
+//
+// When 100 requests a second are hitting the server, the ID lets you grep
+// every log line from one request. It also goes back in the X-Request-ID header,
+// so a user's bug report can include it.
+func requestLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := newRequestID()
+
+		// Every request carries a context.Context, and a context can carry values.
+		// WithContext makes a copy of the request with our ID inside.
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		r = r.WithContext(ctx)
+
+		w.Header().Set("X-Request-ID", id)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		log.Printf("%s %s %s %d %v", id, r.Method, r.URL.Path, recorder.status, time.Since(start))
+	})
+}
+
+// requestIDFromContext gets the ID requestLogMiddleware stored, "" if there isn't one.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string) // Value returns any, so type assert back to string
+	return id
+}
+
+// newRequestID makes a random UUID (version 4) looking string,
+// like python's str(uuid.uuid4()).
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)         // crypto/rand never fails on supported platforms
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // standard variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// statusRecorder remembers the status code a handler wrote.
+//
+// http.ResponseWriter doesn't let you read the status back, so we embed
+// it (every method passes through) and override just WriteHeader to peek.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap hands back the real ResponseWriter, so http.NewResponseController
+// can still Flush through us (see stockEventsHandler).
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// post sends body as JSON and returns "status body", like get.
+func post(url, body string) string {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%d %s", resp.StatusCode, strings.TrimSpace(string(respBody))) // Encode adds a newline
+}
+
+// get makes a GET request and returns "status body", errors included.
+func get(url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close() // ALWAYS close response bodies, or the connection leaks
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%d %s", resp.StatusCode, body)
+}
@@ -0,0 +1,236 @@
+package main
+
+//	go test go_9_http.go go_9_http_test.go
+//
+// httptest again: NewRecorder to call a handler directly, no network at all,
+// and NewServer when we need a real connection (streaming, hanging up).
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureLogs sends the log package's output to a buffer until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &logs
+}
+
+func TestPanicGives500AndTheServerStaysUp(t *testing.T) {
+	captureLogs(t)
+	server := httptest.NewServer(newRouter())
+	defer server.Close()
+
+	if got := strings.TrimSpace(get(server.URL + "/panic")); got != "500 Internal Server Error" {
+		t.Errorf("GET /panic = %q, want a 500", got)
+	}
+	if got := get(server.URL + "/hello"); got != "200 hello!" {
+		t.Errorf("GET /hello after the panic = %q, want 200 hello!", got)
+	}
+}
+
+func TestRecoverMiddlewareLogsThePanic(t *testing.T) {
+	logs := captureLogs(t)
+	recorder := httptest.NewRecorder()
+	recoverMiddleware(newRouter()).ServeHTTP(recorder, httptest.NewRequest("GET", "/panic", nil))
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(logs.String(), "panic serving GET /panic") {
+		t.Errorf("got %d, logs %q, want a 500 and the panic logged", recorder.Code, logs)
+	}
+}
+
+func TestRecoverMiddlewareLetsErrAbortHandlerThrough(t *testing.T) {
+	handler := recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler panicked again", recovered)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	t.Error("ServeHTTP returned, the panic was swallowed")
+}
+
+func TestRequestIDInTheLogAndTheHandler(t *testing.T) {
+	logs := captureLogs(t)
+	var seen string
+	handler := requestLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestIDFromContext(r.Context())
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/whoami", nil))
+
+	if len(seen) != 36 { // 32 hex digits and 4 dashes
+		t.Fatalf("handler saw request ID %q, want a UUID", seen)
+	}
+	if header := recorder.Header().Get("X-Request-ID"); header != seen {
+		t.Errorf("X-Request-ID = %q, want %q", header, seen)
+	}
+	if !strings.Contains(logs.String(), seen+" GET /whoami 200 ") { // after log's timestamp
+		t.Errorf("log = %q, want the same ID", logs)
+	}
+}
+
+func TestRequestIDContextRoundTrip(t *testing.T) {
+	ctx := context.WithValue(context.Background(), requestIDKey, "abc-123")
+	if got := requestIDFromContext(ctx); got != "abc-123" {
+		t.Errorf("requestIDFromContext = %q, want abc-123", got)
+	}
+	if got := requestIDFromContext(context.Background()); got != "" {
+		t.Errorf("no ID: got %q, want \"\"", got)
+	}
+	// A plain int key with the same value is a different key, that's what ctxKey is for.
+	if got := requestIDFromContext(context.WithValue(context.Background(), 0, "nope")); got != "" {
+		t.Errorf("int key 0: got %q, want \"\"", got)
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	handler := Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls = append(calls, "handler") }),
+		traceMiddleware("first", &calls),
+		traceMiddleware("second", &calls),
+	)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	want := []string{"first", "second", "handler", "second", "first"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestGracefulShutdownFinishesTheRequest(t *testing.T) {
+	status, err := demoGracefulShutdown()
+	if status != "200 slow but done" || err != nil {
+		t.Errorf("demoGracefulShutdown = %q, %v, want the slow request to finish and a clean shutdown", status, err)
+	}
+}
+
+func TestCreateUserHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"valid", `{"name": "alice", "password": "Gopher123"}`,
+			http.StatusCreated, `{"name":"alice","password":"****"}`},
+		{"weak password", `{"name": "alice", "password": "gopher123"}`,
+			http.StatusUnprocessableEntity, `{"errors":{"password":"must have an upper case letter"}}`},
+		{"everything wrong", `{"name": " ", "password": "short"}`,
+			http.StatusUnprocessableEntity, `{"errors":{"name":"is required","password":"must be at least 8 characters"}}`},
+		{"unknown field", `{"name": "bob", "password": "Gopher456", "admin": true}`,
+			http.StatusBadRequest, `{"errors":{"body":"json: unknown field \"admin\""}}`},
+		{"not json", `{"name": "bob"`,
+			http.StatusBadRequest, `{"errors":{"body":"unexpected EOF"}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			createUserHandler(recorder, httptest.NewRequest("POST", "/users", strings.NewReader(test.body)))
+			if got := strings.TrimSpace(recorder.Body.String()); recorder.Code != test.wantCode || got != test.wantBody {
+				t.Errorf("got %d %s, want %d %s", recorder.Code, got, test.wantCode, test.wantBody)
+			}
+		})
+	}
+}
+
+func TestStockEvents(t *testing.T) {
+	captureLogs(t)
+	server := httptest.NewServer(newRouter())
+	defer server.Close()
+
+	t.Run("reads a few then hangs up", func(t *testing.T) {
+		events, err := readEvents(server.URL+"/stocks?every=100ms", 3)
+		want := []string{"🍎 AAPL", "🤓 GOOG", "🤢 FB"}
+		if err != nil || !slices.Equal(events, want) {
+			t.Errorf("readEvents = %q, %v, want %q", events, err, want)
+		}
+	})
+
+	t.Run("every is clamped", func(t *testing.T) {
+		start := time.Now()
+		if _, err := readEvents(server.URL+"/stocks?every=1ns", 3); err != nil {
+			t.Fatal(err)
+		}
+		if took := time.Since(start); took < 2*minEventInterval {
+			t.Errorf("3 events took %v, want at least 2 waits of %v", took, minEventInterval)
+		}
+	})
+
+	for _, every := range []string{"0s", "-1s", "soon"} {
+		if got := get(server.URL + "/stocks?every=" + every); !strings.HasPrefix(got, "400 ") {
+			t.Errorf("every=%s: got %q, want a 400", every, got)
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	captureLogs(t)
+	server := httptest.NewServer(newRouter())
+	defer server.Close()
+
+	for _, path := range []string{"/hello", "/hello", "/panic"} {
+		get(server.URL + path)
+	}
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE http_requests_total counter",
+		"http_requests_total 3",
+		"http_request_errors_total 1",
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_bucket{le="+Inf"} 3`,
+		"http_request_duration_seconds_count 3",
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("metrics are missing %q, got:\n%s", line, body)
+		}
+	}
+}
+
+func TestHTTPMetricsSnapshot(t *testing.T) {
+	metrics := &httpMetrics{}
+	handler := metrics.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	for _, path := range []string{"/", "/", "/broken"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	snapshot := metrics.Snapshot()
+	var bucketed int64
+	for _, bucket := range snapshot.Buckets {
+		bucketed += bucket.Count
+	}
+	if snapshot.Requests != 3 || snapshot.Errors != 1 || bucketed != 3 {
+		t.Errorf("snapshot = %+v, want 3 requests, 1 error, 3 in the buckets", snapshot)
+	}
+	if last := snapshot.Buckets[len(snapshot.Buckets)-1]; last.UpTo != 0 {
+		t.Errorf("last bucket UpTo = %v, want 0 for the catch all", last.UpTo)
+	}
+}